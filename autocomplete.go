@@ -0,0 +1,151 @@
+package godiscord
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AutocompleteFunc is called while a user is typing in an option with autocomplete enabled.
+// focused is the partially typed value of the option, and opts holds the values of the other options filled in so far.
+// At most 25 choices can be returned.
+type AutocompleteFunc func(f *Fetcher, interaction Interaction, focused string, opts SlashCommandOptions) ([]ApplicationCommandOptionChoice, error)
+
+// InteractionAutocompleteData is the data of an APPLICATION_COMMAND_AUTOCOMPLETE_RESULT response.
+type InteractionAutocompleteData struct {
+	Choices []ApplicationCommandOptionChoice `json:"choices"` // Autocomplete choices (max of 25 choices).
+}
+
+// RegisterAutocomplete registers an autocomplete handler for an option of an already registered slash command,
+// and enables autocomplete for that option.
+// command is the name of the command, followed by the subcommand group and subcommand if any, e.g. "search users".
+// The option must be of type STRING, INTEGER or NUMBER, and can't have predefined choices.
+func (b *Bot) RegisterAutocomplete(command, option string, handler AutocompleteFunc) error {
+	path := strings.Fields(command)
+	if len(path) == 0 {
+		return fmt.Errorf("command name must be set")
+	}
+
+	b.commandsMu.Lock()
+	defer b.commandsMu.Unlock()
+
+	cmd, ok := b.slashCommands[path[0]]
+	if !ok {
+		return fmt.Errorf("slash command %q is not registered", path[0])
+	}
+
+	options := cmd.definition.Options
+	for _, name := range path[1:] {
+		found := false
+		for _, o := range options {
+			if o.Name == name && (o.Type == ApplicationCommandOptionTypeSubCommand || o.Type == ApplicationCommandOptionTypeSubCommandGroup) {
+				options = o.Options
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("subcommand %q not found in %q", name, command)
+		}
+	}
+
+	for i, o := range options {
+		if o.Name != option {
+			continue
+		}
+
+		switch o.Type {
+		case ApplicationCommandOptionTypeString, ApplicationCommandOptionTypeInteger, ApplicationCommandOptionTypeNumber:
+		default:
+			return fmt.Errorf("autocomplete is only supported for STRING, INTEGER and NUMBER options")
+		}
+
+		if len(o.Choices) > 0 {
+			return fmt.Errorf("autocomplete can't be used on an option with choices")
+		}
+
+		// options shares its backing array with the bot's copy of the definition, so this enables it in what is synced.
+		options[i].Autocomplete = true
+		cmd.autocomplete[strings.Join(append(path[1:], option), " ")] = handler
+
+		return nil
+	}
+
+	return fmt.Errorf("option %q not found in %q", option, command)
+}
+
+func (b *Bot) handleAutocomplete(fetcher *Fetcher, interaction Interaction) error {
+	data, err := interaction.CommandData()
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal command data: %w", err)
+	}
+
+	b.commandsMu.RLock()
+	command, ok := b.slashCommands[data.Name]
+	b.commandsMu.RUnlock()
+	if !ok {
+		b.logger.Warn("Received autocomplete for unknown application command.", "name", data.Name)
+		return nil
+	}
+
+	opts := newSlashCommandOptions(data)
+
+	var focused *ApplicationCommandInteractionDataOption
+	for _, option := range opts.options {
+		if option.Focused {
+			option := option
+			focused = &option
+			break
+		}
+	}
+
+	if focused == nil {
+		b.logger.Warn("Received autocomplete without a focused option.", "command", data.Name)
+		return nil
+	}
+
+	key := strings.Join(append(opts.Subcommand, focused.Name), " ")
+	b.commandsMu.RLock()
+	handler, ok := command.autocomplete[key]
+	b.commandsMu.RUnlock()
+	if !ok {
+		b.logger.Warn("Received autocomplete for an option without a handler.", "command", data.Name, "option", key)
+		return nil
+	}
+
+	// The focused value is usually sent as a string, even for numeric options, since the user may not have finished typing.
+	value, err := UnmarshalJSON[string](focused.Value)
+	if err != nil {
+		value = string(focused.Value)
+	}
+
+	choices, err := handler(fetcher, interaction, value, opts)
+	if err != nil {
+		return fmt.Errorf("autocomplete handler of %s option %s failed: %w", data.Name, key, err)
+	}
+
+	if err := fetcher.Responder(interaction).Autocomplete(choices); err != nil {
+		return fmt.Errorf("failed to respond to autocomplete of %s option %s: %w", data.Name, key, err)
+	}
+
+	return nil
+}
+
+// Autocomplete responds to an autocomplete interaction with suggested choices.
+// Only the first 25 choices are sent.
+func (r *InteractionResponder) Autocomplete(choices []ApplicationCommandOptionChoice) error {
+	if len(choices) > 25 {
+		r.restClient.logger.Warn("Too many autocomplete choices, truncating to 25.", "choices", len(choices))
+		choices = choices[:25]
+	}
+
+	if choices == nil {
+		// Discord requires the field to be an array, even if empty.
+		choices = []ApplicationCommandOptionChoice{}
+	}
+
+	return r.Respond(InteractionResponse{
+		Type: InteractionCallbackApplicationCommandAutocompleteResult,
+		Data: InteractionAutocompleteData{Choices: choices},
+	})
+}
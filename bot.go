@@ -2,78 +2,163 @@ package godiscord
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
-	"math"
-	"math/rand"
 	"net/http"
 	"regexp"
+	"runtime/debug"
+	"slices"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/hagesjo/webgockets"
 )
 
-func NewBot(token, prefix string) (*Bot, error) {
-	restClient, err := newRestClient(&http.Client{}, token)
+// defaultShutdownTimeout is how long a shutdown waits for a running handler to finish.
+const defaultShutdownTimeout = 10 * time.Second
+
+// defaultPrefix is the prefix of text commands unless WithPrefix is used.
+const defaultPrefix = "!"
+
+// NewBot creates a bot, configured by the given options.
+func NewBot(token string, opts ...Option) (*Bot, error) {
+	bot := &Bot{
+		shutdownTimeout: defaultShutdownTimeout,
+
+		token:           token,
+		prefix:          defaultPrefix,
+		textCommands:    make(map[string]TextCommandFunc),
+		slashCommands:   make(map[string]*slashCommand),
+		componentRoutes: newCustomIDRouter[ComponentFunc](),
+		modalRoutes:     newCustomIDRouter[ModalFunc](),
+		eventListeners:  make(map[string][]*eventListener),
+
+		unavailableGuilds: make(map[string]Guild),
+		fetchersByGuild:   make(map[string]*Fetcher),
+		voiceJoins:        make(map[string]*voiceJoin),
+
+		cache:       NewMemoryStateCache(CacheAll, 0),
+		logger:      slog.Default(),
+		httpClient:  &http.Client{},
+		restBaseURL: defaultRESTBaseURL,
+		encoding:    EncodingJSON,
+		intents:     IntentsDefault,
+	}
+
+	for _, opt := range opts {
+		opt(bot)
+	}
+
+	if err := errors.Join(bot.optionErrs...); err != nil {
+		return nil, err
+	}
+
+	if bot.encoding != EncodingJSON && bot.encoding != EncodingETF {
+		return nil, fmt.Errorf("unknown encoding %q", bot.encoding)
+	}
+
+	if bot.shardCount < 0 {
+		return nil, fmt.Errorf("invalid shard count %d", bot.shardCount)
+	}
+
+	for _, id := range bot.shardIDs {
+		if bot.shardCount == 0 || id < 0 || id >= bot.shardCount {
+			return nil, fmt.Errorf("invalid shard id %d for shard count %d", id, bot.shardCount)
+		}
+	}
+
+	restClient, err := newRestClient(bot.httpClient, bot.restBaseURL, token, bot.logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initiate rest client: %w", err)
 	}
+	bot.restClient = restClient
 
-	gatewayURL, err := restClient.GetGatewayURL()
+	gateway, err := restClient.GetGatewayBot(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get gateway url: %w", err)
 	}
 
-	wsClient, err := webgockets.NewClient(gatewayURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create websocket client: %w", err)
+	gatewayURL := gateway.URL
+	if bot.gatewayURL != "" {
+		gatewayURL = bot.gatewayURL
 	}
 
-	return &Bot{
-		wsClient:   wsClient,
-		restClient: restClient,
-
-		token:          token,
-		gatewayURL:     gatewayURL, // NOTE: discord doesn't want this cached too long.
-		prefix:         prefix,
-		textCommands:   make(map[string]TextCommandFunc),
-		eventListeners: make(map[string]eventHandler),
+	// NOTE: discord doesn't want the gateway url cached too long.
+	bot.shardManager = newShardManager(bot, bot.gatewayURLWithParams(gatewayURL), gateway)
 
-		guilds:            make(map[string]Guild),
-		unavailableGuilds: make(map[string]Guild),
-		fetchersByGuild:   make(map[string]*Fetcher),
-	}, nil
+	return bot, nil
 }
 
 type (
 	TextCommandFunc func(*Fetcher, []string, Channel) error
 	EventListenFunc func(*Fetcher, DispatchEvent) error
+	// ErrorFunc handles an error of handling an event, see Bot.OnError. event is the name of the event, e.g. MESSAGE_CREATE.
+	ErrorFunc func(ctx context.Context, event string, err error)
 )
 
 type Bot struct {
-	wsClient   *webgockets.Client
-	restClient *restClient
-	prefix     string
+	// mu guards the fields used to stop the bot, the connection state handlers and the presence.
+	mu                      sync.Mutex
+	stop                    context.CancelFunc
+	stopped                 chan struct{}
+	connectionStateHandlers []func(ConnectionStateChange)
+
+	shutdownTimeout time.Duration
+
+	shardManager *ShardManager
+	restClient   *restClient
+	prefix       string
+	logger       *slog.Logger
+
+	token       string
+	compression bool
+	encoding    Encoding
+	userID      string
+
+	// Identify and connection settings, see the options.
+	intents        int
+	presence       *Presence
+	largeThreshold int
+	shardCount     int   // Total number of shards, 0 to use the number recommended by discord.
+	shardIDs       []int // Shards run by this bot, all of them if empty.
+	httpClient     *http.Client
+	restBaseURL    string
+	gatewayURL     string  // Overrides the gateway url from discord if set.
+	optionErrs     []error // Errors of invalid options, returned by NewBot.
+
+	// commandsMu guards the commands and what's needed to sync them, as commands can be registered and synced
+	// while events are handled.
+	commandsMu     sync.RWMutex
+	textCommands   map[string]TextCommandFunc
+	slashCommands  map[string]*slashCommand
+	applicationID  string
+	commandsSynced bool // Whether the slash commands were synced, or are being synced, on READY.
 
-	token             string
-	gatewayURL        string
-	heartbeatInterval int
-	lastSequence      *uint64
-	resumeGatewayURL  string
-	sessionID         string
+	componentRoutes *customIDRouter[ComponentFunc]
+	modalRoutes     *customIDRouter[ModalFunc]
 
-	textCommands   map[string]TextCommandFunc
-	eventListeners map[string]eventHandler
+	// listenersMu guards the event listeners and the error handler, which can be set at any time.
+	listenersMu    sync.Mutex
+	eventListeners map[string][]*eventListener // By event name, in the order they were registered.
+	errorHandler   ErrorFunc                   // Logs the errors if nil.
+
+	cache StateCache
 
+	// guildsMu guards the unavailable guilds and the fetchers of the guilds.
+	// Events update them under the write lock, before being passed on to the handlers.
+	guildsMu          sync.RWMutex
 	unavailableGuilds map[string]Guild
-	guilds            map[string]Guild
 	fetchersByGuild   map[string]*Fetcher
+
+	// voiceMu guards the pending voice channel joins, by guild id.
+	voiceMu    sync.Mutex
+	voiceJoins map[string]*voiceJoin
 }
 
 // RegisterTextCommand registers a text command.
-// handler will be called when a text is received in either DM:s or in a channel.
+// handler will be called when a text is received in either DM:s or in a channel. In DM:s, the fetcher has no guild
+// and the channel is of type ChannelTypeDM.
 // command must be a single word, only include alphanumeric and -_, and it should start with a letter.
 func (b *Bot) RegisterTextCommand(command string, handler TextCommandFunc) error {
 	command = strings.ToLower(strings.TrimPrefix(command, b.prefix))
@@ -82,36 +167,172 @@ func (b *Bot) RegisterTextCommand(command string, handler TextCommandFunc) error
 		return fmt.Errorf("invalid command name")
 	}
 
+	b.commandsMu.Lock()
 	b.textCommands[command] = handler
+	b.commandsMu.Unlock()
 
 	return nil
 }
 
-// RegisterEventListener registers an event listener.
+// eventListener is a registered event listener.
+type eventListener struct {
+	handler eventHandler
+	once    bool // Whether the listener is unregistered after being run once.
+}
+
+// RegisterEventListener registers an event listener, and returns a function unregistering it.
 // Handler must be in the form of func(*Fetcher, <GuildEvent>) error {}.
+// Any number of listeners can be registered for the same event, and they're run in the order they were registered.
 // Might need a refactor or another look to try get rid of the `any` and have a generic function signature.
 // Not entirely sure how yet though.
-func (b *Bot) RegisterEventListener(handler any) error {
+func (b *Bot) RegisterEventListener(handler any) (unregister func(), err error) {
 	eventHandler, err := eventHandlerFromInterface(handler)
 	if err != nil {
-		return fmt.Errorf("failed to register event: %w", err)
+		return nil, fmt.Errorf("failed to register event: %w", err)
 	}
 
-	b.eventListeners[eventHandler.name()] = eventHandler
+	return b.addEventListener(eventHandler, false), nil
+}
 
-	return nil
+// Once registers an event listener that's only run for the next event of its type, see RegisterEventListener.
+// The returned function unregisters it, if it hasn't been run yet.
+func (b *Bot) Once(handler any) (unregister func(), err error) {
+	eventHandler, err := eventHandlerFromInterface(handler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register event: %w", err)
+	}
+
+	return b.addEventListener(eventHandler, true), nil
+}
+
+// On registers a listener for events of type T, and returns a function unregistering it.
+// The listeners of a shard are run one at a time, in the order the events were received, so a slow listener holds up
+// the later events of its shard. Interactions are passed on to their command and component handlers without waiting.
+// Unlike RegisterEventListener, the type of the listener is checked at compile time:
+//
+//	godiscord.On(bot, func(f *godiscord.Fetcher, mc godiscord.MessageCreate) error {
+//		...
+//	})
+func On[T DispatchEvent](b *Bot, listener func(*Fetcher, T) error) (unregister func()) {
+	return b.addEventListener(listenerFunc[T](listener), false)
+}
+
+// OnOnce is like On, but the listener is only run for the next event of type T.
+func OnOnce[T DispatchEvent](b *Bot, listener func(*Fetcher, T) error) (unregister func()) {
+	return b.addEventListener(listenerFunc[T](listener), true)
 }
 
-func (b *Bot) ListGuilds() (guilds []Guild) {
-	for _, guild := range b.guilds {
-		guilds = append(guilds, guild)
+func (b *Bot) addEventListener(eventHandler eventHandler, once bool) func() {
+	listener := &eventListener{
+		handler: eventHandler,
+		once:    once,
 	}
 
-	return guilds
+	b.listenersMu.Lock()
+	defer b.listenersMu.Unlock()
+
+	name := eventHandler.name()
+	b.eventListeners[name] = append(b.eventListeners[name], listener)
+
+	return func() {
+		b.removeEventListener(name, listener)
+	}
+}
+
+// removeEventListener unregisters a listener, returning false if it wasn't registered.
+func (b *Bot) removeEventListener(name string, listener *eventListener) bool {
+	b.listenersMu.Lock()
+	defer b.listenersMu.Unlock()
+
+	listeners := b.eventListeners[name]
+	i := slices.Index(listeners, listener)
+	if i < 0 {
+		return false
+	}
+
+	// The slice is copied, as it may be iterated over by a running dispatch.
+	b.eventListeners[name] = slices.Delete(slices.Clone(listeners), i, i+1)
+
+	return true
+}
+
+func (b *Bot) hasEventListeners(eventType string) bool {
+	b.listenersMu.Lock()
+	defer b.listenersMu.Unlock()
+
+	return len(b.eventListeners[eventType]) > 0
+}
+
+// runEventListeners runs the listeners of an event, in the order they were registered.
+// All listeners are run even if some fail or panic, and their errors are passed on to the error handler.
+func (b *Bot) runEventListeners(ctx context.Context, eventType string, fetcher *Fetcher, ev any) {
+	b.listenersMu.Lock()
+	listeners := b.eventListeners[eventType]
+	b.listenersMu.Unlock()
+
+	for _, listener := range listeners {
+		// A one-shot listener is only run by whoever manages to unregister it.
+		if listener.once && !b.removeEventListener(eventType, listener) {
+			continue
+		}
+
+		err := recoverPanic(func() error {
+			return listener.handler.run(fetcher, ev)
+		})
+		if err != nil {
+			b.handleError(ctx, eventType, fmt.Errorf("event listener failed: %w", err))
+		}
+	}
+}
+
+// OnError sets the handler of the errors returned by event listeners and command handlers, or of panics in them,
+// replacing any previous one. It's also given the events that can't be decoded, which are dropped.
+// None of these errors disconnect the bot. By default, they're logged.
+//
+// ctx is done once the bot is shutting down.
+func (b *Bot) OnError(handler ErrorFunc) {
+	b.listenersMu.Lock()
+	defer b.listenersMu.Unlock()
+
+	b.errorHandler = handler
+}
+
+// handleError passes an error of handling an event to the error handler.
+func (b *Bot) handleError(ctx context.Context, eventType string, err error) {
+	b.listenersMu.Lock()
+	handler := b.errorHandler
+	b.listenersMu.Unlock()
+
+	if handler == nil {
+		b.logger.Error("Failed to handle event.", "type", eventType, "error", err)
+		return
+	}
+
+	handler(ctx, eventType, err)
+}
+
+// recoverPanic calls fn, returning a panic in it as an error.
+func recoverPanic(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	return fn()
+}
+
+// ListGuilds returns the guilds the bot is in.
+//
+// Like all getters of the cache, it's safe to call from any goroutine. The returned values are copies taken at the
+// time of the call, and aren't updated by later events. They must not be modified, as they may share slices and
+// pointers with the cache.
+func (b *Bot) ListGuilds() []Guild {
+	return b.cache.Guilds()
 }
 
 func (b *Bot) GetGuildByID(id string) (Guild, error) {
-	guild, ok := b.guilds[id]
+	guild, ok := b.cache.Guild(id)
 	if !ok {
 		return guild, fmt.Errorf("guild not found")
 	}
@@ -128,8 +349,33 @@ func (b *Bot) GetGuildByName(name string) (Guild, error) {
 	return Guild{}, fmt.Errorf("not found")
 }
 
-func (b *Bot) GetVoiceStates(guildID string) ([]VoiceState, error) {
+// fetcher returns the fetcher of a guild.
+func (b *Bot) fetcher(guildID string) (*Fetcher, bool) {
+	b.guildsMu.RLock()
+	defer b.guildsMu.RUnlock()
+
 	f, ok := b.fetchersByGuild[guildID]
+	return f, ok
+}
+
+// fetcherFor returns the fetcher to give to the handlers of an event received by shard in the given guild.
+// Events outside of a guild, such as those of direct messages, get a fetcher without a guild. So do events of
+// guilds not known yet, rather than being dropped.
+// The fetcher carries ctx, the context of the run, so that the requests of the handlers are canceled on shutdown.
+func (b *Bot) fetcherFor(ctx context.Context, shard *Shard, guildID string) *Fetcher {
+	if guildID == "" {
+		return newFetcher("", b.restClient, shard, b.cache).withContext(ctx)
+	}
+
+	if f, ok := b.fetcher(guildID); ok {
+		return f.withContext(ctx)
+	}
+
+	return newFetcher(guildID, b.restClient, shard, b.cache).withContext(ctx)
+}
+
+func (b *Bot) GetVoiceStates(guildID string) ([]VoiceState, error) {
+	f, ok := b.fetcher(guildID)
 	if !ok {
 		return nil, fmt.Errorf("no such guild")
 	}
@@ -138,7 +384,7 @@ func (b *Bot) GetVoiceStates(guildID string) ([]VoiceState, error) {
 }
 
 func (b *Bot) GetChannelsByIDs(guildID string, channelIDs ...string) ([]Channel, error) {
-	f, ok := b.fetchersByGuild[guildID]
+	f, ok := b.fetcher(guildID)
 	if !ok {
 		return nil, fmt.Errorf("no such guild")
 	}
@@ -147,7 +393,7 @@ func (b *Bot) GetChannelsByIDs(guildID string, channelIDs ...string) ([]Channel,
 }
 
 func (b *Bot) GetMembers(guildID string) ([]GuildMember, error) {
-	f, ok := b.fetchersByGuild[guildID]
+	f, ok := b.fetcher(guildID)
 	if !ok {
 		return nil, fmt.Errorf("no such guild")
 	}
@@ -156,7 +402,7 @@ func (b *Bot) GetMembers(guildID string) ([]GuildMember, error) {
 }
 
 func (b *Bot) GetMembersByIDs(guildID string, memberIDs ...string) ([]GuildMember, error) {
-	f, ok := b.fetchersByGuild[guildID]
+	f, ok := b.fetcher(guildID)
 	if !ok {
 		return nil, fmt.Errorf("no such guild")
 	}
@@ -170,7 +416,11 @@ func (b *Bot) SendMessage(guildName, channelName, message string) error {
 		return fmt.Errorf("failed to get guild: %w", err)
 	}
 
-	f := b.fetchersByGuild[g.ID]
+	f, ok := b.fetcher(g.ID)
+	if !ok {
+		return fmt.Errorf("no such guild")
+	}
+
 	c, found := f.GetChannelByName(channelName)
 	if !found {
 		return fmt.Errorf("no channel found")
@@ -183,248 +433,351 @@ func (b *Bot) SendMessage(guildName, channelName, message string) error {
 	return nil
 }
 
+// Run connects to the gateway and handles events until an unrecoverable error occurs.
 func (b *Bot) Run() error {
-	canReconnect := false
-	connectRetries := 0
-	for {
-		if err := b.wsClient.Connect(); err != nil {
-			slog.Info("Trying to connect.", "attempt", connectRetries+1)
-
-			if connectRetries > 10 {
-				return fmt.Errorf("maximum connect attempts tried: %w", err)
-			}
+	return b.RunContext(context.Background())
+}
 
-			// TODO: Make it less arbitrary with the sleep time and max attempts.
-			time.Sleep(5 * time.Second)
-			connectRetries += 1
-			continue
-		}
+// RunContext is like Run, but shuts down gracefully when ctx is done or Close is called.
+// On shutdown, the heartbeater is stopped and no more events are read. A handler that is currently
+// running is given up to the shutdown timeout to finish, after which the connection is closed and nil is returned.
+func (b *Bot) RunContext(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		connectRetries = 0
+	b.mu.Lock()
+	if b.stop != nil {
+		b.mu.Unlock()
+		return fmt.Errorf("bot is already running")
+	}
 
-		var err error
-		canReconnect, err = b.handler(canReconnect)
-		if err != nil {
-			return fmt.Errorf("handler failed: %w", err)
-		}
+	stopped := make(chan struct{})
+	b.stop = cancel
+	b.stopped = stopped
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		b.stop = nil
+		b.stopped = nil
+		b.mu.Unlock()
+
+		close(stopped)
+	}()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- b.shardManager.run(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		b.shardManager.closeConnections()
+		return err
+	case <-ctx.Done():
+	}
 
-		if canReconnect {
-			b.wsClient, err = webgockets.NewClient(b.resumeGatewayURL)
-			if err != nil {
-				return fmt.Errorf("failed to create websocket client: %w", err)
-			}
-		}
+	b.logger.Info("Shutting down, waiting for running handlers to finish.")
+
+	timer := time.NewTimer(b.shutdownTimeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-timer.C:
+		b.logger.Warn("Timed out waiting for handlers to finish.", "timeout", b.shutdownTimeout)
 	}
+
+	b.shardManager.closeConnections()
+
+	return err
 }
 
-// handler is the main listening loop for the bot, blocking until a disconnect happens.
-// The returned bool describes if a reconnect is possible or not.
-// TODO: Should be made context aware from args.
-func (b *Bot) handler(isResuming bool) (bool, error) {
-	heartbeatCtx, cancelHeartbeatCtx := context.WithCancel(context.Background())
-	defer cancelHeartbeatCtx()
+// Close stops a running bot and waits for RunContext to return.
+// It does nothing if the bot isn't running.
+func (b *Bot) Close() error {
+	b.mu.Lock()
+	stop, stopped := b.stop, b.stopped
+	b.mu.Unlock()
 
-	ctx, cancel := context.WithCancel(heartbeatCtx)
-	defer cancel()
+	if stop == nil {
+		return nil
+	}
+
+	stop()
+	<-stopped
+
+	return nil
+}
+
+// ShardManager returns the shard manager running the gateway connections of the bot.
+func (b *Bot) ShardManager() *ShardManager {
+	return b.shardManager
+}
+
+// dispatch handles a dispatch event received by a shard. The cache is updated right away, after which the event is
+// queued to the shard's worker, which passes it on to the handlers and listeners. The shard therefore keeps reading
+// events while the handlers run, and the shards handle their events in parallel. The handlers of interactions are
+// started right away rather than queued, as they must respond within 3 seconds. The member chunks and voice updates
+// awaited by Fetcher.RequestMembers and Bot.JoinVoiceChannel are matched while updating the cache, so handlers can
+// wait for them.
+//
+// Only an event that the session can't go on without is returned as an error. Events that can't be decoded and the
+// errors of the handlers are given to the error handler instead, so that a change of an event by discord or a failing
+// handler doesn't disconnect the bot.
+func (b *Bot) dispatch(ctx context.Context, shard *Shard, event Event) error {
+	if event.Type == nil {
+		return fmt.Errorf("discord sent dispatch without type set")
+	}
 
-	heartbeatStarted := false
-	var heartbeatErr error
+	eventType := *event.Type
 
-	for {
-		var closeErr *webgockets.ErrClose
-		event, err := webgockets.ReadJSON[Event](ctx, b.wsClient)
-		if errors.As(err, &closeErr) {
-			return ResumeableCloseEvents[int(closeErr.Code)], nil
-		} else if errors.Is(err, context.Canceled) {
-			return true, nil
-		} else if err != nil {
-			return false, fmt.Errorf("failed to read event: %w", err)
+	var ev any
+	err := recoverPanic(func() error {
+		if event.Data == nil {
+			return fmt.Errorf("discord sent %s without data", eventType)
 		}
 
-		if event.SequenceNumber != nil {
-			b.lastSequence = event.SequenceNumber
+		var err error
+		ev, err = b.updateCache(shard, eventType, *event.Data)
+		return err
+	})
+	if err != nil {
+		// The session id and resume url come with READY.
+		if eventType == "READY" {
+			return err
 		}
 
-		switch event.OpCode {
-		case OpCodeDispatch:
-			slog.Info("Got dispatch.", "type", *event.Type, "event", event)
-			if err := b.handleDispatch(*event); err != nil {
-				return false, fmt.Errorf("failed to handle dispatch event: %w", err)
-			}
-		case OpCodeResume:
-			// Do nothing for now.
-		case OpCodeReconnect:
-			return true, nil
-		case OpCodeInvalidSession:
-			slog.Info("Got invalid session.", "event", event)
-			if canResume, err := UnmarshalJSON[bool](*event.Data); err != nil {
-				return false, fmt.Errorf("discord sent invalid 'invalid session': %w", err)
-			} else {
-				return canResume, nil
-			}
-		case OpCodeHello:
-			hello, err := UnmarshalJSON[Hello](*event.Data)
-			if err != nil {
-				return false, fmt.Errorf("discord sent invalid hello '%s': %w", *event.Data, err)
-			}
+		b.handleError(ctx, eventType, err)
+		return nil
+	}
+
+	switch eventType {
+	case "READY", "RESUMED":
+		// The session is established, or the resume worked and all missed events have been replayed.
+		shard.setState(ConnectionStateConnected, nil)
+	}
 
-			slog.Info("Got hello event.", "event", fmt.Sprintf("%#v", event), "hello", hello)
+	if _, ok := ev.(Ready); ok {
+		b.syncCommandsOnReady(ctx)
+	}
 
-			b.heartbeatInterval = hello.HeartbeatInterval
+	if e, ok := ev.(InteractionCreate); ok {
+		// Interactions must be responded to within 3 seconds, so they don't wait for the handlers of earlier events.
+		shard.worker.start(func() {
+			b.handleInteractionCreate(ctx, shard, eventType, e)
+		})
+	}
 
-			if !isResuming {
-				if err := b.identify(); err != nil {
-					return false, fmt.Errorf("failed to identify: %w", err)
-				}
-			} else {
-				if err := b.resume(); err != nil {
-					return false, fmt.Errorf("failed to resume: %w", err)
-				}
-			}
+	shard.worker.enqueue(func() {
+		b.handleEvent(ctx, shard, eventType, ev)
+	})
 
-			if heartbeatStarted {
-				continue
-			}
+	return nil
+}
 
-			go func(ctx context.Context) {
-				heartbeatErr = b.heartbeater(ctx)
-				if heartbeatErr != nil {
-					cancelHeartbeatCtx()
-				}
-			}(heartbeatCtx)
-			heartbeatStarted = true
+// handleInteractionCreate passes an interaction on to its handler. It's started by the shard's worker without
+// waiting for the queued events.
+func (b *Bot) handleInteractionCreate(ctx context.Context, shard *Shard, eventType string, e InteractionCreate) {
+	err := recoverPanic(func() error {
+		return b.handleInteraction(ctx, shard, e.Interaction)
+	})
+	if err != nil {
+		b.handleError(ctx, eventType, fmt.Errorf("failed to handle interaction: %w", err))
+	}
+}
 
-		case OpCodeHeartbeatAck:
-			slog.Info("Got heartbeat ack event.")
-		default:
-			slog.Info("Got unknown event.", "event", fmt.Sprintf("%#v", event))
+// handleEvent passes a dispatch event on to the text commands and listeners. It's run by the shard's worker.
+func (b *Bot) handleEvent(ctx context.Context, shard *Shard, eventType string, ev any) {
+	if e, ok := ev.(MessageCreate); ok {
+		err := recoverPanic(func() error {
+			return b.handleTextCommand(ctx, shard, e)
+		})
+		if err != nil {
+			b.handleError(ctx, eventType, err)
 		}
 	}
+
+	if e, ok := ev.(guildEvent); ok && b.hasEventListeners(eventType) {
+		b.runEventListeners(ctx, eventType, b.fetcherFor(ctx, shard, e.guild()), ev)
+	}
 }
 
-// handleDispatch handles all dispatch events sent which the cache needs to act on.
-// For the majority of the event, it will do nothing.
-// It will log a warning if an unknown dispatch event is received.
-func (b *Bot) handleDispatch(event Event) error {
-	if event.Type == nil {
-		return fmt.Errorf("discord sent dispatch without type set")
+// handleTextCommand runs the text command of a message, if it's one.
+func (b *Bot) handleTextCommand(ctx context.Context, shard *Shard, messageCreate MessageCreate) error {
+	if !strings.HasPrefix(messageCreate.Content, b.prefix) {
+		return nil
 	}
 
-	eventType := *event.Type
+	s := strings.Fields(strings.TrimPrefix(messageCreate.Content, b.prefix))
+	if len(s) == 0 {
+		return nil
+	}
+
+	b.commandsMu.RLock()
+	command, ok := b.textCommands[s[0]]
+	b.commandsMu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	fetcher := b.fetcherFor(ctx, shard, messageCreate.GuildID)
+
+	// DM channels aren't cached, as they're not part of a guild.
+	channel, ok := fetcher.GetChannelByID(messageCreate.ChannelID)
+	if !ok && messageCreate.GuildID == "" {
+		channel = Channel{ID: messageCreate.ChannelID, Type: ChannelTypeDM}
+	}
+
+	if err := command(fetcher, s[1:], channel); err != nil {
+		return fmt.Errorf("text command %s failed: %w", s[0], err)
+	}
+
+	return nil
+}
+
+// updateCache updates the cache with a dispatch event, and returns the decoded event.
+// For the majority of the events, it will only decode them.
+// It will log a warning if an unknown dispatch event is received.
+func (b *Bot) updateCache(shard *Shard, eventType string, data json.RawMessage) (any, error) {
+	b.guildsMu.Lock()
+	defer b.guildsMu.Unlock()
 
 	var ev any
 
 	switch eventType {
 	case "READY":
-		readyEvent, err := UnmarshalJSON[Ready](*event.Data)
+		readyEvent, err := UnmarshalJSON[Ready](data)
 		if err != nil {
-			return fmt.Errorf("failed to unmarshal create guild json: %w", err)
+			return nil, fmt.Errorf("failed to unmarshal ready json: %w", err)
 		}
 
-		for _, guild := range b.guilds {
+		for _, guild := range readyEvent.Guilds {
 			if guild.Unavailable {
 				b.unavailableGuilds[guild.ID] = guild
 			} else {
-				b.guilds[guild.ID] = guild
+				b.cache.SetGuild(guild)
 			}
 		}
 
-		b.resumeGatewayURL = fmt.Sprintf("%s?v=%d&encoding=json", readyEvent.ResumeGatewayURL, apiVersion)
-		b.sessionID = readyEvent.SessionID
+		shard.resumeGatewayURL = b.gatewayURLWithParams(readyEvent.ResumeGatewayURL)
+		shard.sessionID = readyEvent.SessionID
+		b.commandsMu.Lock()
+		b.applicationID = readyEvent.Application.ID
+		b.commandsMu.Unlock()
+
+		b.userID = readyEvent.User.ID
+
+		ev = readyEvent
 	case "RESUMED":
-		// TODO: Use this to ensure a resume worked.
-	case "APPLICATION_COMMAND_PERMISSIONS_UPDATE":
-		ev = MustUnmarshalJSON[ApplicationCommandPermissionsUpdate](*event.Data)
-	case "AUTO_MODERATION_RULE_CREATE":
-		ev = MustUnmarshalJSON[AutoModerationRuleCreate](*event.Data)
-	case "AUTO_MODERATION_RULE_UPDATE":
-		ev = MustUnmarshalJSON[AutoModerationRuleUpdate](*event.Data)
-	case "AUTO_MODERATION_RULE_DELETE":
-		ev = MustUnmarshalJSON[AutoModerationRuleDelete](*event.Data)
+		resumed, err := UnmarshalJSON[Resumed](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+
+		ev = resumed
 	case "CHANNEL_CREATE":
-		channelCreate := MustUnmarshalJSON[ChannelUpdate](*event.Data)
+		channelCreate, err := UnmarshalJSON[ChannelCreate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
 		channel := channelCreate.Channel
 
+		// Channels outside of a guild, such as DMs, aren't cached but are still given to the listeners.
+		ev = channelCreate
+
 		if channel.GuildID == nil {
 			break
 		}
 
-		f, ok := b.fetchersByGuild[*channel.GuildID]
-		if !ok {
-			slog.Warn("CHANNEL_CREATE received with a channel outside of a known guild", "guild", *channel.GuildID)
+		if _, ok := b.fetchersByGuild[*channel.GuildID]; !ok {
+			b.logger.Warn("CHANNEL_CREATE received with a channel outside of a known guild", "guild", *channel.GuildID)
 			break
 		}
 
-		f.channelsByID[channel.ID] = channel
-
-		ev = channelCreate
+		b.cache.SetChannel(*channel.GuildID, channel)
 	case "CHANNEL_UPDATE":
-		channelUpdate := MustUnmarshalJSON[ChannelUpdate](*event.Data)
+		channelUpdate, err := UnmarshalJSON[ChannelUpdate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
 		channel := channelUpdate.Channel
 
+		// Channels outside of a guild, such as DMs, aren't cached but are still given to the listeners.
+		ev = channelUpdate
+
 		if channel.GuildID == nil {
 			break
 		}
 
-		f, ok := b.fetchersByGuild[*channel.GuildID]
-		if !ok {
-			slog.Warn("CHANNEL_UPDATE received with a channel outside of a known guild", "guild", *channel.GuildID)
+		if _, ok := b.fetchersByGuild[*channel.GuildID]; !ok {
+			b.logger.Warn("CHANNEL_UPDATE received with a channel outside of a known guild", "guild", *channel.GuildID)
 			break
 		}
 
-		f.channelsByID[channel.ID] = channel
-
-		ev = channelUpdate
+		b.cache.SetChannel(*channel.GuildID, channel)
 	case "CHANNEL_DELETE":
-		channelDelete := MustUnmarshalJSON[ChannelDelete](*event.Data)
+		channelDelete, err := UnmarshalJSON[ChannelDelete](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
 		channel := channelDelete.Channel
 
+		// Channels outside of a guild, such as DMs, aren't cached but are still given to the listeners.
+		ev = channelDelete
+
 		if channel.GuildID == nil {
 			break
 		}
 
-		f, ok := b.fetchersByGuild[*channel.GuildID]
-		if !ok {
-			slog.Warn("CHANNEL_DELETE received with a channel outside of a known guild", "guild", *channel.GuildID)
+		if _, ok := b.fetchersByGuild[*channel.GuildID]; !ok {
+			b.logger.Warn("CHANNEL_DELETE received with a channel outside of a known guild", "guild", *channel.GuildID)
 			break
 		}
 
-		delete(f.channelsByID, channel.ID)
-
-		ev = channelDelete
-	case "ENTITLEMENT_CREATE":
-		ev = MustUnmarshalJSON[EntitlementCreate](*event.Data)
-	case "ENTITLEMENT_UPDATE":
-		ev = MustUnmarshalJSON[EntitlementUpdate](*event.Data)
-	case "ENTITLEMENT_DELETE":
-		ev = MustUnmarshalJSON[EntitlementDelete](*event.Data)
+		b.cache.DeleteChannel(*channel.GuildID, channel.ID)
 	case "GUILD_CREATE":
-		guildEvent := MustUnmarshalJSON[GuildCreate](*event.Data)
+		guildEvent, err := UnmarshalJSON[GuildCreate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
 
 		if guildEvent.Unavailable != nil && *guildEvent.Unavailable {
 			b.unavailableGuilds[guildEvent.ID] = guildEvent.Guild
 		} else {
-			b.guilds[guildEvent.ID] = guildEvent.Guild
+			delete(b.unavailableGuilds, guildEvent.ID)
+			b.cacheGuild(guildEvent)
 		}
 
-		b.fetchersByGuild[guildEvent.ID] = newFetcher(guildEvent, b.restClient)
+		b.fetchersByGuild[guildEvent.ID] = newFetcher(guildEvent.ID, b.restClient, shard, b.cache)
 
 		ev = guildEvent
 	case "GUILD_UPDATE":
-		guildUpdate := MustUnmarshalJSON[GuildUpdate](*event.Data)
+		guildUpdate, err := UnmarshalJSON[GuildUpdate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
 
-		b.guilds[guildUpdate.Guild.ID] = guildUpdate.Guild
+		b.cache.SetGuild(guildUpdate.Guild)
 
 		ev = guildUpdate
 	case "GUILD_DELETE":
-		guildDelete := MustUnmarshalJSON[GuildDelete](*event.Data)
+		guildDelete, err := UnmarshalJSON[GuildDelete](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
 
 		delete(b.fetchersByGuild, guildDelete.Guild.ID)
-		delete(b.guilds, guildDelete.Guild.ID)
+		b.cache.DeleteGuild(guildDelete.Guild.ID)
 
 		ev = guildDelete
 	case "THREAD_CREATE":
-		thread := MustUnmarshalJSON[ThreadCreate](*event.Data)
+		thread, err := UnmarshalJSON[ThreadCreate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		ev = thread
 
 		if thread.Channel.GuildID == nil {
 			break
@@ -432,17 +785,18 @@ func (b *Bot) handleDispatch(event Event) error {
 
 		channel := thread.Channel
 
-		f, ok := b.fetchersByGuild[*channel.GuildID]
-		if !ok {
-			slog.Warn("THREAD_CREATE received with a channel outside of a known guild", "guild", *channel.GuildID)
+		if _, ok := b.fetchersByGuild[*channel.GuildID]; !ok {
+			b.logger.Warn("THREAD_CREATE received with a channel outside of a known guild", "guild", *channel.GuildID)
 			break
 		}
 
-		f.threadsByID[channel.ID] = channel
-
-		ev = thread
+		b.cache.SetThread(*channel.GuildID, channel)
 	case "THREAD_UPDATE":
-		thread := MustUnmarshalJSON[ThreadUpdate](*event.Data)
+		thread, err := UnmarshalJSON[ThreadUpdate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		ev = thread
 
 		if thread.Channel.GuildID == nil {
 			break
@@ -450,17 +804,18 @@ func (b *Bot) handleDispatch(event Event) error {
 
 		channel := thread.Channel
 
-		f, ok := b.fetchersByGuild[*channel.GuildID]
-		if !ok {
-			slog.Warn("THREAD_UPDATE received with a channel outside of a known guild", "guild", *channel.GuildID)
+		if _, ok := b.fetchersByGuild[*channel.GuildID]; !ok {
+			b.logger.Warn("THREAD_UPDATE received with a channel outside of a known guild", "guild", *channel.GuildID)
 			break
 		}
 
-		f.threadsByID[channel.ID] = channel
-
-		ev = thread
+		b.cache.SetThread(*channel.GuildID, channel)
 	case "THREAD_DELETE":
-		thread := MustUnmarshalJSON[ThreadDelete](*event.Data)
+		thread, err := UnmarshalJSON[ThreadDelete](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		ev = thread
 
 		if thread.Channel.GuildID == nil {
 			break
@@ -468,92 +823,92 @@ func (b *Bot) handleDispatch(event Event) error {
 
 		channel := thread.Channel
 
-		f, ok := b.fetchersByGuild[*channel.GuildID]
-		if !ok {
-			slog.Warn("THREAD_DELETE received with a channel outside of a known guild", "guild", *channel.GuildID)
+		if _, ok := b.fetchersByGuild[*channel.GuildID]; !ok {
+			b.logger.Warn("THREAD_DELETE received with a channel outside of a known guild", "guild", *channel.GuildID)
 			break
 		}
 
-		delete(f.threadsByID, channel.ID)
-
-		ev = thread
+		b.cache.DeleteThread(*channel.GuildID, channel.ID)
 	case "THREAD_LIST_SYNC":
-		listSyncEvent := MustUnmarshalJSON[ThreadListSync](*event.Data)
+		listSyncEvent, err := UnmarshalJSON[ThreadListSync](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		ev = listSyncEvent
 
-		f, ok := b.fetchersByGuild[listSyncEvent.GuildID]
-		if !ok {
-			slog.Warn("THREAD_LIST_SYNC received with a channel outside of a known guild", "guild", listSyncEvent.GuildID)
+		if _, ok := b.fetchersByGuild[listSyncEvent.GuildID]; !ok {
+			b.logger.Warn("THREAD_LIST_SYNC received with a channel outside of a known guild", "guild", listSyncEvent.GuildID)
 			break
 		}
 
 		for _, thread := range listSyncEvent.Threads {
-			f.threadsByID[thread.ID] = thread
+			b.cache.SetThread(listSyncEvent.GuildID, thread)
 		}
-
-		ev = listSyncEvent
-	case "THREAD_MEMBERS_UPDATE":
-		ev = MustUnmarshalJSON[ThreadMembersUpdate](*event.Data)
-	case "THREAD_MEMBER_UPDATE":
-		ev = MustUnmarshalJSON[ThreadMemberUpdate](*event.Data)
-	case "GUILD_AUDIT_LOG_ENTRY_CREATE":
-		// Do nothing.
 	case "GUILD_EMOJIS_UPDATE":
-		emojisUpdate := MustUnmarshalJSON[GuildEmojisUpdate](*event.Data)
+		emojisUpdate, err := UnmarshalJSON[GuildEmojisUpdate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
 
-		guild, ok := b.guilds[emojisUpdate.GuildID]
-		if !ok {
-			slog.Warn("GUILD_EMOJIS_UPDATE sent guild_id outside of a known guild", "guild", emojisUpdate.GuildID)
+		if _, ok := b.fetchersByGuild[emojisUpdate.GuildID]; !ok {
+			b.logger.Warn("GUILD_EMOJIS_UPDATE sent guild_id outside of a known guild", "guild", emojisUpdate.GuildID)
 		}
 
-		guild.Emojis = emojisUpdate.Emojis
-		b.guilds[guild.ID] = guild
+		b.cache.SetEmojis(emojisUpdate.GuildID, emojisUpdate.Emojis)
 
 		ev = emojisUpdate
 	case "GUILD_STICKERS_UPDATE":
-		stickersUpdate := MustUnmarshalJSON[GuildStickersUpdate](*event.Data)
-
-		guild, ok := b.guilds[stickersUpdate.GuildID]
-		if !ok {
-			slog.Warn("GUILD_STICKERS_UPDATE sent guild_id outside of a known guild", "guild", stickersUpdate.GuildID)
+		stickersUpdate, err := UnmarshalJSON[GuildStickersUpdate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
 		}
 
-		guild.Stickers = stickersUpdate.Stickers
-		b.guilds[guild.ID] = guild
+		if guild, ok := b.cache.Guild(stickersUpdate.GuildID); ok {
+			guild.Stickers = stickersUpdate.Stickers
+			b.cache.SetGuild(guild)
+		} else if _, ok := b.fetchersByGuild[stickersUpdate.GuildID]; !ok {
+			b.logger.Warn("GUILD_STICKERS_UPDATE sent guild_id outside of a known guild", "guild", stickersUpdate.GuildID)
+		}
 
 		ev = stickersUpdate
 	case "GUILD_MEMBER_ADD":
-		memberAdd := MustUnmarshalJSON[GuildMemberAdd](*event.Data)
+		memberAdd, err := UnmarshalJSON[GuildMemberAdd](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		ev = memberAdd
 
-		f, ok := b.fetchersByGuild[memberAdd.GuildID]
-		if !ok {
-			slog.Warn("GUILD_MEMBER_ADD received with a channel outside of a known guild", "guild", memberAdd.GuildID)
+		if _, ok := b.fetchersByGuild[memberAdd.GuildID]; !ok {
+			b.logger.Warn("GUILD_MEMBER_ADD received with a channel outside of a known guild", "guild", memberAdd.GuildID)
 			break
 		}
 
-		f.membersByID[memberAdd.User.ID] = memberAdd.GuildMember
-
-		ev = memberAdd
+		b.cache.SetMember(memberAdd.GuildID, memberAdd.GuildMember)
 	case "GUILD_MEMBER_UPDATE":
-		memberUpdate := MustUnmarshalJSON[GuildMemberUpdate](*event.Data)
+		memberUpdate, err := UnmarshalJSON[GuildMemberUpdate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		ev = memberUpdate
 
-		f, ok := b.fetchersByGuild[memberUpdate.GuildID]
-		if !ok {
-			slog.Warn("GUILD_MEMBER_UPDATE received with a channel outside of a known guild", "guild", memberUpdate.GuildID)
+		if _, ok := b.fetchersByGuild[memberUpdate.GuildID]; !ok {
+			b.logger.Warn("GUILD_MEMBER_UPDATE received with a channel outside of a known guild", "guild", memberUpdate.GuildID)
 			break
 		}
 
-		member, ok := f.membersByID[memberUpdate.User.ID]
-		if !ok {
-			slog.Warn("GUILD_MEMBER_UPDATE received with unknown user", "user_id", memberUpdate.User.ID)
-			break
+		// The member might not be cached, e.g. when evicted from the member cache, in which case the fields
+		// missing from the update are left empty.
+		member, _ := b.cache.Member(memberUpdate.GuildID, memberUpdate.User.ID)
+		if memberUpdate.JoinedAt != nil {
+			member.JoinedAt = *memberUpdate.JoinedAt
 		}
 
-		f.membersByID[memberUpdate.User.ID] = GuildMember{
+		b.cache.SetMember(memberUpdate.GuildID, GuildMember{
 			User:                       &memberUpdate.User,
 			Nick:                       memberUpdate.Nick,
 			Avatar:                     memberUpdate.Avatar,
 			Roles:                      memberUpdate.Roles,
-			JoinedAt:                   *memberUpdate.JoinedAt,
+			JoinedAt:                   member.JoinedAt,
 			PremiumSince:               memberUpdate.PremiumSince,
 			Flags:                      member.Flags,
 			Deaf:                       memberUpdate.Deaf != nil && *memberUpdate.Deaf,
@@ -561,289 +916,206 @@ func (b *Bot) handleDispatch(event Event) error {
 			Pending:                    memberUpdate.Pending,
 			Permissions:                member.Permissions,
 			CommunicationDisabledUntil: memberUpdate.CommunicationDisabledUntil,
-		}
-
-		ev = memberUpdate
+		})
 	case "GUILD_MEMBER_REMOVE":
-		memberRemove := MustUnmarshalJSON[GuildMemberRemove](*event.Data)
+		memberRemove, err := UnmarshalJSON[GuildMemberRemove](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		ev = memberRemove
 
-		f, ok := b.fetchersByGuild[memberRemove.GuildID]
-		if !ok {
-			slog.Warn("GUILD_MEMBER_REMOVE received with a channel outside of a known guild", "guild", memberRemove.GuildID)
+		if _, ok := b.fetchersByGuild[memberRemove.GuildID]; !ok {
+			b.logger.Warn("GUILD_MEMBER_REMOVE received with a channel outside of a known guild", "guild", memberRemove.GuildID)
 			break
 		}
 
-		delete(f.membersByID, memberRemove.User.ID)
-
-		ev = memberRemove
+		b.cache.DeleteMember(memberRemove.GuildID, memberRemove.User.ID)
+		b.cache.DeletePresence(memberRemove.GuildID, memberRemove.User.ID)
 	case "GUILD_MEMBERS_CHUNK":
-		chunk := MustUnmarshalJSON[GuildMembersChunk](*event.Data)
+		chunk, err := UnmarshalJSON[GuildMembersChunk](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		shard.memberChunkReceived(chunk)
+		ev = chunk
 
-		f, ok := b.fetchersByGuild[chunk.GuildID]
-		if !ok {
-			slog.Warn("GUILD_MEMBERS_CHUNK received with a channel outside of a known guild", "guild", chunk.GuildID)
+		if _, ok := b.fetchersByGuild[chunk.GuildID]; !ok {
+			b.logger.Warn("GUILD_MEMBERS_CHUNK received with a channel outside of a known guild", "guild", chunk.GuildID)
 			break
 		}
 
 		for _, member := range chunk.Members {
-			if member.User == nil {
-				continue
-			}
-
-			f.membersByID[member.User.ID] = member
+			b.cache.SetMember(chunk.GuildID, member)
 		}
-
-		ev = chunk
 	case "GUILD_ROLE_CREATE":
-		create := MustUnmarshalJSON[GuildRoleCreate](*event.Data)
+		create, err := UnmarshalJSON[GuildRoleCreate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
 
-		guild, ok := b.guilds[create.GuildID]
-		if !ok {
-			slog.Warn("GUILD_ROLE_CREATE sent guild_id outside of a known guild", "guild", create.GuildID)
+		if _, ok := b.fetchersByGuild[create.GuildID]; !ok {
+			b.logger.Warn("GUILD_ROLE_CREATE sent guild_id outside of a known guild", "guild", create.GuildID)
 		}
 
-		guild.Roles = append(guild.Roles, create.Role)
-		b.guilds[guild.ID] = guild
+		b.cache.SetRole(create.GuildID, create.Role)
 
 		ev = create
 	case "GUILD_ROLE_UPDATE":
-		update := MustUnmarshalJSON[GuildRoleCreate](*event.Data)
-
-		guild, ok := b.guilds[update.GuildID]
-		if !ok {
-			slog.Warn("GUILD_ROLE_UPDATE sent guild_id outside of a known guild", "guild", update.GuildID)
+		update, err := UnmarshalJSON[GuildRoleUpdate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
 		}
 
-		for i, role := range guild.Roles {
-			if role.ID != update.Role.ID {
-				continue
-			}
-
-			guild.Roles[i] = update.Role
-			break
+		if _, ok := b.fetchersByGuild[update.GuildID]; !ok {
+			b.logger.Warn("GUILD_ROLE_UPDATE sent guild_id outside of a known guild", "guild", update.GuildID)
 		}
 
-		guild.Roles = append(guild.Roles, update.Role)
-		b.guilds[guild.ID] = guild
+		b.cache.SetRole(update.GuildID, update.Role)
 
 		ev = update
 	case "GUILD_ROLE_DELETE":
-		delete := MustUnmarshalJSON[GuildRoleCreate](*event.Data)
-
-		guild, ok := b.guilds[delete.GuildID]
-		if !ok {
-			slog.Warn("GUILD_ROLE_DELETE sent guild_id outside of a known guild", "guild", delete.GuildID)
+		delete, err := UnmarshalJSON[GuildRoleDelete](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
 		}
 
-		for i, role := range guild.Roles {
-			if role.ID != delete.Role.ID {
-				continue
-			}
-
-			guild.Roles = append(guild.Roles[:i], guild.Roles[i+1:]...)
-			break
+		if _, ok := b.fetchersByGuild[delete.GuildID]; !ok {
+			b.logger.Warn("GUILD_ROLE_DELETE sent guild_id outside of a known guild", "guild", delete.GuildID)
 		}
 
-		b.guilds[guild.ID] = guild
+		b.cache.DeleteRole(delete.GuildID, delete.RoleID)
 
 		ev = delete
-	case "GUILD_SCHEDULED_EVENT_CREATE":
-		ev = MustUnmarshalJSON[GuildScheduledEventCreate](*event.Data)
-	case "GUILD_SCHEDULED_EVENT_UPDATE":
-		ev = MustUnmarshalJSON[GuildScheduledEventUpdate](*event.Data)
-	case "GUILD_SCHEDULED_EVENT_DELETE":
-		ev = MustUnmarshalJSON[GuildScheduledEventDelete](*event.Data)
-	case "GUILD_SCHEDULED_EVENT_USER_REMOVE_EVENT":
-		ev = MustUnmarshalJSON[guildScheduledEventUserRemoveEventHandler](*event.Data)
-	case "GUILD_SCHEDULED_EVENT_USER_ADD_EVENT":
-		ev = MustUnmarshalJSON[guildScheduledEventUserAddEventHandler](*event.Data)
-	case "INTEGRATION_CREATE":
-		ev = MustUnmarshalJSON[IntegrationCreate](*event.Data)
-	case "INTEGRATION_UPDATE":
-		ev = MustUnmarshalJSON[IntegrationUpdate](*event.Data)
-	case "INTEGRATION_DELETE":
-		ev = MustUnmarshalJSON[IntegrationDelete](*event.Data)
-	case "INVITE_CREATE":
-		ev = MustUnmarshalJSON[InviteCreate](*event.Data)
-	case "INVITE_DELETE":
-		ev = MustUnmarshalJSON[InviteDelete](*event.Data)
-	case "MESSAGE_CREATE":
-		messageCreate := MustUnmarshalJSON[MessageCreate](*event.Data)
-
-		if strings.HasPrefix(messageCreate.Content, b.prefix) {
-			s := strings.Fields(messageCreate.Content[1:])
-			command, ok := b.textCommands[s[0]]
-			if ok {
-				fetcher := b.fetchersByGuild[messageCreate.GuildID]
-				channel := fetcher.channelsByID[messageCreate.ChannelID]
-
-				if err := command(fetcher, s[1:], channel); err != nil {
-				}
-			}
+	case "PRESENCE_UPDATE":
+		presence, err := UnmarshalJSON[PresenceUpdate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
 		}
+		ev = presence
 
-		ev = messageCreate
-	case "MESSAGE_UPDATE":
-		ev = MustUnmarshalJSON[MessageUpdate](*event.Data)
-	case "MESSAGE_DELETE":
-		ev = MustUnmarshalJSON[MessageDelete](*event.Data)
-	case "MESSAGE_DELETE_BULK":
-		ev = MustUnmarshalJSON[MessageDeleteBulk](*event.Data)
-	case "MESSAGE_REACTION_ADD":
-		ev = MustUnmarshalJSON[MessageReactionAdd](*event.Data)
-	case "MESSAGE_REACTION_REMOVE":
-		ev = MustUnmarshalJSON[MessageReactionRemove](*event.Data)
-	case "MESSAGE_REACTION_REMOVE_ALL":
-		ev = MustUnmarshalJSON[MessageReactionRemoveAll](*event.Data)
-	case "MESSAGE_REACTION_REMOVE_EMOJI":
-		ev = MustUnmarshalJSON[MessageReactionRemoveEmoji](*event.Data)
-	case "PRESENCE_UPDATE":
-		// Do nothing.
-	case "STAGE_INSTANCE_CREATE":
-		ev = MustUnmarshalJSON[StageInstanceCreate](*event.Data)
-	case "STAGE_INSTANCE_UPDATE":
-		ev = MustUnmarshalJSON[StageInstanceUpdate](*event.Data)
-	case "STAGE_INSTANCE_DELETE":
-		ev = MustUnmarshalJSON[StageInstanceDelete](*event.Data)
-		// Do nothing.
-	case "TYPING_START":
-		ev = MustUnmarshalJSON[TypingStart](*event.Data)
-		// Do nothing.
+		if _, ok := b.fetchersByGuild[presence.GuildID]; !ok {
+			b.logger.Warn("PRESENCE_UPDATE received with a presence outside of a known guild", "guild", presence.GuildID)
+			break
+		}
+
+		// Offline members have no presence worth keeping.
+		if presence.Status == UserStatusOffline {
+			b.cache.DeletePresence(presence.GuildID, presence.User.ID)
+		} else {
+			b.cache.SetPresence(presence.GuildID, presence)
+		}
 	case "USER_UPDATE":
-		userUpdate := MustUnmarshalJSON[UserUpdate](*event.Data)
+		userUpdate, err := UnmarshalJSON[UserUpdate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
 
-		for _, f := range b.fetchersByGuild {
-			member, ok := f.membersByID[userUpdate.User.ID]
+		for guildID := range b.fetchersByGuild {
+			member, ok := b.cache.Member(guildID, userUpdate.User.ID)
 			if !ok {
 				continue
 			}
 
 			member.User = &userUpdate.User
-			f.membersByID[userUpdate.User.ID] = member
+			b.cache.SetMember(guildID, member)
 		}
 
 		ev = userUpdate
 	case "VOICE_STATE_UPDATE":
-		voiceEvent := MustUnmarshalJSON[VoiceStateUpdate](*event.Data)
+		voiceEvent, err := UnmarshalJSON[VoiceStateUpdate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		ev = voiceEvent
+
 		if voiceEvent.GuildID == nil {
 			break
 		}
 
-		fetcher, ok := b.fetchersByGuild[*voiceEvent.GuildID]
-		if !ok {
-			slog.Warn("VOICE_STATE_UPDATE received with a channel outside of a known guild", "guild", *voiceEvent.GuildID)
+		b.voiceStateUpdated(voiceEvent.VoiceState)
+
+		if _, ok := b.fetchersByGuild[*voiceEvent.GuildID]; !ok {
+			b.logger.Warn("VOICE_STATE_UPDATE received with a channel outside of a known guild", "guild", *voiceEvent.GuildID)
+			break
 		}
 
 		if voiceEvent.ChannelID == nil {
-			delete(fetcher.voiceStatesByID, voiceEvent.UserID)
+			b.cache.DeleteVoiceState(*voiceEvent.GuildID, voiceEvent.UserID)
 		} else {
-			fetcher.voiceStatesByID[voiceEvent.UserID] = voiceEvent.VoiceState
+			b.cache.SetVoiceState(*voiceEvent.GuildID, voiceEvent.VoiceState)
 		}
-
 	case "VOICE_SERVER_UPDATE":
-		ev = MustUnmarshalJSON[VoiceServerUpdate](*event.Data)
-	case "WEBHOOKS_UPDATE":
-		ev = MustUnmarshalJSON[WebhooksUpdate](*event.Data)
+		serverUpdate, err := UnmarshalJSON[VoiceServerUpdate](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		b.voiceServerUpdated(serverUpdate)
+		ev = serverUpdate
 	default:
-		slog.Warn("Unparsed dispatch event", "type", eventType)
-	}
-
-	switch e := ev.(type) {
-	case guildEvent:
-		if eventHandler, ok := b.eventListeners[eventType]; ok {
-			fetcher, ok := b.fetchersByGuild[e.guild()]
-			if !ok {
-				return fmt.Errorf("no fetcher found for guild")
-			}
+		decode, ok := eventDecoders[eventType]
+		if !ok {
+			b.logger.Warn("Unparsed dispatch event", "type", eventType)
+			break
+		}
 
-			return eventHandler.run(fetcher, ev)
+		decoded, err := decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", eventType, err)
 		}
+
+		ev = decoded
 	}
 
-	return nil
+	return ev, nil
 }
 
-func (b *Bot) identify() error {
-	identifyPayload := Identify{
-		OpCode: OpCodeIdentity,
-		Payload: IdentifyPayload{
-			Token: b.token,
-			Intents: IntentGuilds | IntentGuildMembers | IntentGuildModeration | IntentGuildEmojisAndStickers |
-				IntentGuildIntegrations | IntentGuildWebhooks | IntentGuildInvites | IntentGuildVoiceStates |
-				IntentGuildPresences | IntentGuildMessages | IntentGuildMessageReactions | IntentGuildMessageTyping |
-				IntentDirectMessages | IntentDirectMessageReactions | IntentDirectMessageTyping | IntentMessageContent |
-				IntentGuildScheduledEvents | IntentAutoModerationConfiguration | IntentAutoModerationExecution,
-			Properties: Properties{
-				OS:      "raspbian",
-				Browser: "webgockets",
-				Device:  "godiscord",
-			},
-			Presence: &Presence{
-				Activities: []*Activity{
-					{
-						Name: "developing simulator or something",
-						Type: ActivityGame,
-						URL:  "https://github.com/Hagesjo/godiscord",
-					},
-				},
-				Status: UserStatusOnline,
-				AFK:    false,
-			},
-		},
-	}
-
-	if _, err := b.wsClient.WriteJSON(identifyPayload); err != nil {
-		return fmt.Errorf("failed to identify: %w", err)
-	}
+// cacheGuild caches a guild, along with the state sent with it in GUILD_CREATE.
+func (b *Bot) cacheGuild(guildEvent GuildCreate) {
+	guildID := guildEvent.ID
 
-	return nil
-}
+	b.cache.SetGuild(guildEvent.Guild)
 
-func (b *Bot) resume() error {
-	resume := Resume{
-		OpCode: OpCodeResume,
-		Payload: ResumePayload{
-			Token:     b.token,
-			SessionID: b.sessionID,
-		},
+	for _, channel := range guildEvent.Channels {
+		b.cache.SetChannel(guildID, channel)
 	}
 
-	if b.lastSequence != nil {
-		resume.Payload.LastSequence = *b.lastSequence
+	for _, thread := range guildEvent.Threads {
+		b.cache.SetThread(guildID, thread)
 	}
 
-	if _, err := b.wsClient.WriteJSON(resume); err != nil {
-		return fmt.Errorf("failed to resume: %w", err)
+	membersByID := make(map[string]GuildMember, len(guildEvent.Members))
+	for _, member := range guildEvent.Members {
+		if member.User == nil {
+			continue
+		}
+
+		membersByID[member.User.ID] = member
+		b.cache.SetMember(guildID, member)
 	}
 
-	return nil
-}
+	for _, voiceState := range guildEvent.VoiceStates {
+		// Discord does not provide member nor guild id in voice states for GUILD_CREATE for whatever reason.
+		// So we fill them in.
+		voiceState.GuildID = &guildID
+		if member, ok := membersByID[voiceState.UserID]; ok {
+			voiceState.Member = &member
+		}
 
-func (b *Bot) heartbeater(ctx context.Context) error {
-	if b.heartbeatInterval == 0 {
-		return fmt.Errorf("heartbeatInterval must be > 0")
+		b.cache.SetVoiceState(guildID, voiceState)
 	}
 
-	intervalWithJitter := func(interval int) time.Duration {
-		return time.Duration(math.Round(rand.Float64()*float64(interval))) * time.Millisecond
+	for _, presence := range guildEvent.Presences {
+		presence.GuildID = guildID
+		b.cache.SetPresence(guildID, presence)
 	}
+}
 
-	ticker := time.NewTicker(intervalWithJitter(b.heartbeatInterval))
-
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			if _, err := b.wsClient.WriteJSON(Heartbeat{
-				OpCode: OpCodeHeartbeat,
-			}); err != nil {
-				return fmt.Errorf("failed to send heartbeat: %w", err)
-			}
-
-			slog.Info("Sent heartbeat")
-
-			ticker.Reset(intervalWithJitter(b.heartbeatInterval))
-		}
+// gatewayURLWithParams adds the query parameters used when connecting to a gateway url.
+func (b *Bot) gatewayURLWithParams(gatewayURL string) string {
+	u := fmt.Sprintf("%s?v=%d&encoding=%s", gatewayURL, apiVersion, b.encoding)
+	if b.compression {
+		u += "&compress=zlib-stream"
 	}
+
+	return u
 }
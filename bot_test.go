@@ -0,0 +1,426 @@
+package godiscord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestBot returns a bot set up like NewBot does, without connecting to discord.
+func newTestBot() *Bot {
+	return &Bot{
+		shutdownTimeout: defaultShutdownTimeout,
+
+		prefix:          defaultPrefix,
+		textCommands:    make(map[string]TextCommandFunc),
+		slashCommands:   make(map[string]*slashCommand),
+		componentRoutes: newCustomIDRouter[ComponentFunc](),
+		modalRoutes:     newCustomIDRouter[ModalFunc](),
+		eventListeners:  make(map[string][]*eventListener),
+
+		unavailableGuilds: make(map[string]Guild),
+		fetchersByGuild:   make(map[string]*Fetcher),
+		voiceJoins:        make(map[string]*voiceJoin),
+
+		cache:    NewMemoryStateCache(CacheAll, 0),
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		encoding: EncodingJSON,
+		intents:  IntentsDefault,
+	}
+}
+
+// newTestShard returns a shard of b whose worker runs until the test is done.
+func newTestShard(t *testing.T, b *Bot) *Shard {
+	shard := &Shard{
+		bot:            b,
+		worker:         newEventWorker(),
+		memberRequests: make(map[string]*memberRequest),
+	}
+
+	go shard.worker.run()
+	t.Cleanup(shard.worker.stop)
+
+	return shard
+}
+
+// testEvent returns a dispatch event as received from discord.
+func testEvent(eventType, data string) Event {
+	raw := json.RawMessage(data)
+	return Event{Type: &eventType, Data: &raw}
+}
+
+func TestDispatchWhileReadingTheCache(t *testing.T) {
+	b := newTestBot()
+	shard := newTestShard(t, b)
+	ctx := context.Background()
+	b.OnError(func(ctx context.Context, event string, err error) {
+		t.Errorf("failed to handle %s: %v", event, err)
+	})
+
+	// The listeners read the cache from the shard's worker, while the shard keeps updating it.
+	On(b, func(f *Fetcher, e GuildMemberAdd) error {
+		f.GetMembers()
+		f.GetChannels()
+		return nil
+	})
+
+	const guilds = 4
+	const rounds = 50
+
+	var wg sync.WaitGroup
+	for g := 0; g < guilds; g++ {
+		guildID := fmt.Sprint(g + 1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < rounds; i++ {
+				user := fmt.Sprintf(`{"id": "%d", "username": "someone", "discriminator": "0", "avatar": null}`, i)
+				for _, event := range []Event{
+					testEvent("GUILD_CREATE", `{"id": "`+guildID+`", "name": "guild", "roles": [], "emojis": [], "channels": [], "threads": [], "members": []}`),
+					testEvent("CHANNEL_CREATE", `{"id": "`+guildID+`0", "type": 0, "guild_id": "`+guildID+`", "name": "channel"}`),
+					testEvent("GUILD_MEMBER_ADD", `{"guild_id": "`+guildID+`", "user": `+user+`, "roles": [], "joined_at": "2023-01-01T00:00:00Z", "deaf": false, "mute": false}`),
+					testEvent("GUILD_MEMBER_UPDATE", `{"guild_id": "`+guildID+`", "user": `+user+`, "roles": ["1"]}`),
+					testEvent("GUILD_ROLE_CREATE", `{"guild_id": "`+guildID+`", "role": {"id": "`+guildID+`1", "name": "role"}}`),
+					testEvent("GUILD_DELETE", `{"id": "`+guildID+`", "unavailable": false}`),
+				} {
+					if err := b.dispatch(ctx, shard, event); err != nil {
+						t.Errorf("failed to dispatch %s: %v", *event.Type, err)
+						return
+					}
+				}
+			}
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < rounds; i++ {
+				b.ListGuilds()
+				b.GetGuildByID(guildID)
+				b.GetVoiceStates(guildID)
+
+				if f, ok := b.fetcher(guildID); ok {
+					f.GetChannelByID(guildID + "0")
+					f.GetMembers()
+					f.GetRoles()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestUpdateCacheReturnsEventsOfUnknownGuilds(t *testing.T) {
+	b := newTestBot()
+	shard := &Shard{memberRequests: make(map[string]*memberRequest)}
+
+	const user = `{"id": "2", "username": "someone", "discriminator": "0", "avatar": null}`
+	const thread = `{"id": "3", "type": 11, "guild_id": "1", "parent_id": "4", "name": "thread"}`
+	events := map[string]string{
+		"THREAD_CREATE":       thread,
+		"THREAD_UPDATE":       thread,
+		"THREAD_DELETE":       `{"id": "3", "type": 11, "guild_id": "1", "parent_id": "4"}`,
+		"THREAD_LIST_SYNC":    `{"guild_id": "1", "threads": [` + thread + `], "members": []}`,
+		"GUILD_MEMBER_ADD":    `{"guild_id": "1", "user": ` + user + `, "roles": [], "joined_at": "2023-01-01T00:00:00Z", "deaf": false, "mute": false}`,
+		"GUILD_MEMBER_UPDATE": `{"guild_id": "1", "user": ` + user + `, "roles": []}`,
+		"GUILD_MEMBER_REMOVE": `{"guild_id": "1", "user": ` + user + `}`,
+		"GUILD_MEMBERS_CHUNK": `{"guild_id": "1", "members": [], "chunk_index": 0, "chunk_count": 1}`,
+		"CHANNEL_CREATE":      `{"id": "4", "type": 0, "guild_id": "1", "name": "channel"}`,
+		"PRESENCE_UPDATE":     `{"guild_id": "1", "user": ` + user + `, "status": "online", "activities": [], "client_status": {}}`,
+		"VOICE_STATE_UPDATE":  `{"guild_id": "1", "channel_id": null, "user_id": "2", "session_id": "s", "deaf": false, "mute": false, "self_deaf": false, "self_mute": false, "self_video": false, "suppress": false, "request_to_speak_timestamp": null}`,
+	}
+
+	for eventType, data := range events {
+		t.Run(eventType, func(t *testing.T) {
+			// Guild 1 isn't known to the bot, so the cache isn't updated but the event is still returned.
+			ev, err := b.updateCache(shard, eventType, []byte(data))
+			if err != nil {
+				t.Fatalf("failed to update cache: %v", err)
+			}
+
+			if ev == nil {
+				t.Errorf("got no event")
+			}
+		})
+	}
+
+	if presences := b.cache.Presences("1"); len(presences) != 0 {
+		t.Errorf("got %d presences of the unknown guild, want 0", len(presences))
+	}
+
+	if members := b.cache.Members("1"); len(members) != 0 {
+		t.Errorf("got %d members of the unknown guild, want 0", len(members))
+	}
+}
+
+func TestUnregisterEventListener(t *testing.T) {
+	b := newTestBot()
+	ctx := context.Background()
+
+	var got []string
+	unregister := On(b, func(f *Fetcher, e MessageCreate) error {
+		got = append(got, "first")
+		return nil
+	})
+	On(b, func(f *Fetcher, e MessageCreate) error {
+		got = append(got, "second")
+		return nil
+	})
+	unregisterOnce, err := b.Once(func(f *Fetcher, e MessageCreate) error {
+		got = append(got, "once")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to register listener: %v", err)
+	}
+
+	unregister()
+	unregisterOnce()
+	// Unregistering twice does nothing.
+	unregister()
+
+	b.runEventListeners(ctx, "MESSAGE_CREATE", nil, MessageCreate{})
+	if len(got) != 1 || got[0] != "second" {
+		t.Errorf("got listeners %v run, want [second]", got)
+	}
+
+	if _, err := b.RegisterEventListener(func(f *Fetcher, e string) error { return nil }); err == nil {
+		t.Errorf("registered a listener of a non-event")
+	}
+}
+
+func TestOnOnceRunsOnce(t *testing.T) {
+	b := newTestBot()
+	shard := newTestShard(t, b)
+	ctx := context.Background()
+
+	ran := make(chan struct{}, 10)
+	OnOnce(b, func(f *Fetcher, e TypingStart) error {
+		ran <- struct{}{}
+		return nil
+	})
+
+	// Two events back to back on the same shard.
+	event := testEvent("TYPING_START", `{"channel_id": "1", "user_id": "2", "timestamp": 0}`)
+	for i := 0; i < 2; i++ {
+		if err := b.dispatch(ctx, shard, event); err != nil {
+			t.Fatalf("failed to dispatch: %v", err)
+		}
+	}
+
+	// Wait for the shard's worker to run the listeners of the dispatched events.
+	done := make(chan struct{})
+	shard.worker.enqueue(func() { close(done) })
+	<-done
+
+	if n := len(ran); n != 1 {
+		t.Fatalf("listener ran %d times, want 1", n)
+	}
+	<-ran
+
+	// And on different shards at the same time.
+	OnOnce(b, func(f *Fetcher, e TypingStart) error {
+		ran <- struct{}{}
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.runEventListeners(ctx, "TYPING_START", nil, TypingStart{})
+		}()
+	}
+	wg.Wait()
+
+	if n := len(ran); n != 1 {
+		t.Errorf("listener ran %d times, want 1", n)
+	}
+
+	if b.hasEventListeners("TYPING_START") {
+		t.Errorf("listeners weren't unregistered")
+	}
+}
+
+func TestDispatchResumed(t *testing.T) {
+	b := newTestBot()
+	shard := newTestShard(t, b)
+
+	resumed := make(chan struct{})
+	OnOnce(b, func(f *Fetcher, e Resumed) error {
+		close(resumed)
+		return nil
+	})
+
+	if err := b.dispatch(context.Background(), shard, testEvent("RESUMED", `{}`)); err != nil {
+		t.Fatalf("failed to dispatch: %v", err)
+	}
+
+	select {
+	case <-resumed:
+	case <-time.After(time.Second):
+		t.Fatalf("listener wasn't run")
+	}
+
+	if state := shard.State(); state != ConnectionStateConnected {
+		t.Errorf("shard is %v, want connected", state)
+	}
+}
+
+func TestDispatchPassesErrorsToOnError(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	const message = `{"id": "5", "channel_id": "4", "author": {"id": "2", "username": "someone", "discriminator": "0", "avatar": null}, "content": "!ping", "timestamp": "2023-01-01T00:00:00Z", "edited_timestamp": null, "tts": false, "mention_everyone": false, "mentions": [], "mention_roles": [], "attachments": [], "embeds": [], "pinned": false, "type": 0}`
+	const typing = `{"channel_id": "4", "user_id": "2", "timestamp": 0}`
+
+	tests := []struct {
+		name     string
+		register func(t *testing.T, b *Bot)
+		event    Event
+		want     string // Substring of the error.
+		wraps    bool   // Whether the error wraps errHandler.
+	}{
+		{
+			name: "panicking listener",
+			register: func(t *testing.T, b *Bot) {
+				On(b, func(f *Fetcher, e TypingStart) error {
+					panic("listener panicked")
+				})
+			},
+			event: testEvent("TYPING_START", typing),
+			want:  "panic: listener panicked",
+		},
+		{
+			name: "failing listener",
+			register: func(t *testing.T, b *Bot) {
+				On(b, func(f *Fetcher, e TypingStart) error {
+					return errHandler
+				})
+			},
+			event: testEvent("TYPING_START", typing),
+			want:  "event listener failed",
+			wraps: true,
+		},
+		{
+			name:     "undecodable event",
+			register: func(t *testing.T, b *Bot) {},
+			event:    testEvent("TYPING_START", `{"channel_id": 4}`),
+			want:     "failed to decode TYPING_START",
+		},
+		{
+			name: "failing autocomplete",
+			register: func(t *testing.T, b *Bot) {
+				err := b.RegisterSlashCommand(SlashCommand{
+					Name:        "search",
+					Description: "Searches",
+					Options: []ApplicationCommandOption{
+						{Type: ApplicationCommandOptionTypeString, Name: "query", Description: "What to search for"},
+					},
+				}, nil)
+				if err != nil {
+					t.Fatalf("failed to register command: %v", err)
+				}
+
+				err = b.RegisterAutocomplete("search", "query", func(f *Fetcher, i Interaction, focused string, opts SlashCommandOptions) ([]ApplicationCommandOptionChoice, error) {
+					return nil, errHandler
+				})
+				if err != nil {
+					t.Fatalf("failed to register autocomplete: %v", err)
+				}
+			},
+			event: testEvent("INTERACTION_CREATE", `{"id": "6", "application_id": "7", "type": 4, "token": "token", "version": 1, "data": {"id": "8", "name": "search", "type": 1, "options": [{"name": "query", "type": 3, "value": "ab", "focused": true}]}}`),
+			want:  "autocomplete handler of search option query failed",
+			wraps: true,
+		},
+		{
+			name: "failing text command",
+			register: func(t *testing.T, b *Bot) {
+				err := b.RegisterTextCommand("ping", func(f *Fetcher, args []string, channel Channel) error {
+					return errHandler
+				})
+				if err != nil {
+					t.Fatalf("failed to register command: %v", err)
+				}
+			},
+			event: testEvent("MESSAGE_CREATE", message),
+			want:  "text command ping failed",
+			wraps: true,
+		},
+		{
+			name: "panicking text command",
+			register: func(t *testing.T, b *Bot) {
+				err := b.RegisterTextCommand("ping", func(f *Fetcher, args []string, channel Channel) error {
+					panic("command panicked")
+				})
+				if err != nil {
+					t.Fatalf("failed to register command: %v", err)
+				}
+			},
+			event: testEvent("MESSAGE_CREATE", message),
+			want:  "panic: command panicked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBot()
+			shard := newTestShard(t, b)
+			tt.register(t, b)
+
+			type handledError struct {
+				event string
+				err   error
+			}
+
+			handled := make(chan handledError, 1)
+			b.OnError(func(ctx context.Context, event string, err error) {
+				handled <- handledError{event, err}
+			})
+
+			// The error doesn't stop the shard.
+			if err := b.dispatch(context.Background(), shard, tt.event); err != nil {
+				t.Fatalf("failed to dispatch: %v", err)
+			}
+
+			select {
+			case got := <-handled:
+				if got.event != *tt.event.Type {
+					t.Errorf("got error of %s, want %s", got.event, *tt.event.Type)
+				}
+
+				if !strings.Contains(got.err.Error(), tt.want) {
+					t.Errorf("got error %q, want it to contain %q", got.err, tt.want)
+				}
+
+				if tt.wraps && !errors.Is(got.err, errHandler) {
+					t.Errorf("got error %q, want it to wrap the handler's error", got.err)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("OnError wasn't called")
+			}
+		})
+	}
+}
+
+func TestDispatchReturnsErrorsOfReady(t *testing.T) {
+	b := newTestBot()
+	shard := newTestShard(t, b)
+	b.OnError(func(ctx context.Context, event string, err error) {
+		t.Errorf("OnError was called with %v", err)
+	})
+
+	// The session can't be resumed without the id of READY, so the shard must reconnect.
+	if err := b.dispatch(context.Background(), shard, testEvent("READY", `{"session_id": 1}`)); err == nil {
+		t.Errorf("dispatched an undecodable READY")
+	}
+}
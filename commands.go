@@ -0,0 +1,392 @@
+package godiscord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SlashCommandFunc is called when a user invokes a registered slash command.
+// The interaction must be responded to within 3 seconds.
+type SlashCommandFunc func(*Fetcher, Interaction, SlashCommandOptions) error
+
+// SlashCommand declares a slash command (CHAT_INPUT application command).
+type SlashCommand struct {
+	Name                     string                     // Name of the command, 1-32 lowercase characters.
+	Description              string                     // Description of the command, 1-100 characters.
+	Options                  []ApplicationCommandOption // Typed parameters of the command, max of 25.
+	DefaultMemberPermissions *string                    // Set of permissions represented as a bit set, required to use the command by default.
+	DMPermission             *bool                      // Whether the command is available in DMs. Only applies to global commands.
+	NSFW                     bool                       // Whether the command is age-restricted.
+
+	// GuildIDs restricts the command to the given guilds. If empty, the command is registered globally.
+	// Guild commands update instantly, which makes them useful during development.
+	GuildIDs []string
+}
+
+type slashCommand struct {
+	definition ApplicationCommand
+	guildIDs   []string
+	handler    SlashCommandFunc
+
+	// autocomplete holds the autocomplete handlers keyed by the option path, e.g. "subcommand option".
+	autocomplete map[string]AutocompleteFunc
+}
+
+// RegisterSlashCommand registers a slash command and its handler. The bot keeps a copy of the command, so it can be
+// reused or modified afterwards. The handler runs as soon as the command is used, without waiting for the event
+// listeners, as the interaction must be responded to within 3 seconds.
+// The definitions are synced to discord when the bot receives its first READY, so this must be called before Run.
+// Only the scopes (global or a specific guild) with at least one registered command are synced,
+// and they are synced with a bulk overwrite, meaning any command in that scope not registered here is removed.
+func (b *Bot) RegisterSlashCommand(command SlashCommand, handler SlashCommandFunc) error {
+	if err := validateCommandName(command.Name); err != nil {
+		return err
+	}
+
+	if l := len([]rune(command.Description)); l < 1 || l > 100 {
+		return fmt.Errorf("description must be between 1 and 100 characters")
+	}
+
+	if len(command.Options) > 25 {
+		return fmt.Errorf("a command can have at most 25 options")
+	}
+
+	for _, option := range command.Options {
+		if err := validateCommandOption(option); err != nil {
+			return fmt.Errorf("invalid option %q: %w", option.Name, err)
+		}
+	}
+
+	b.commandsMu.Lock()
+	defer b.commandsMu.Unlock()
+
+	if _, ok := b.slashCommands[command.Name]; ok {
+		return fmt.Errorf("slash command %q already registered", command.Name)
+	}
+
+	b.slashCommands[command.Name] = &slashCommand{
+		definition: ApplicationCommand{
+			Type:                     ApplicationCommandTypeChatInput,
+			Name:                     command.Name,
+			Description:              command.Description,
+			Options:                  cloneCommandOptions(command.Options),
+			DefaultMemberPermissions: command.DefaultMemberPermissions,
+			DMPermission:             command.DMPermission,
+			NSFW:                     command.NSFW,
+		},
+		guildIDs:     slices.Clone(command.GuildIDs),
+		handler:      handler,
+		autocomplete: make(map[string]AutocompleteFunc),
+	}
+
+	return nil
+}
+
+func validateCommandName(name string) error {
+	re := regexp.MustCompile(`^[-_\p{L}\p{N}]{1,32}$`)
+	if !re.MatchString(name) || strings.ToLower(name) != name {
+		return fmt.Errorf("invalid command name %q", name)
+	}
+
+	return nil
+}
+
+func validateCommandOption(option ApplicationCommandOption) error {
+	if err := validateCommandName(option.Name); err != nil {
+		return err
+	}
+
+	if l := len([]rune(option.Description)); l < 1 || l > 100 {
+		return fmt.Errorf("description must be between 1 and 100 characters")
+	}
+
+	if len(option.Choices) > 25 {
+		return fmt.Errorf("an option can have at most 25 choices")
+	}
+
+	for _, sub := range option.Options {
+		if err := validateCommandOption(sub); err != nil {
+			return fmt.Errorf("invalid option %q: %w", sub.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// SyncCommands pushes all registered slash command definitions to discord.
+// It is called automatically in the background on the first READY, but can be called again if commands are registered
+// later on.
+func (b *Bot) SyncCommands() error {
+	return b.SyncCommandsContext(context.Background())
+}
+
+// SyncCommandsContext is like SyncCommands, but the requests are canceled when ctx is done.
+func (b *Bot) SyncCommandsContext(ctx context.Context) error {
+	// The definitions are copied, so that the requests are made without holding the lock.
+	b.commandsMu.RLock()
+	applicationID := b.applicationID
+
+	var global []ApplicationCommand
+	byGuild := make(map[string][]ApplicationCommand)
+	for _, command := range b.slashCommands {
+		definition := command.definition
+		definition.Options = cloneCommandOptions(definition.Options)
+
+		if len(command.guildIDs) == 0 {
+			global = append(global, definition)
+			continue
+		}
+
+		for _, guildID := range command.guildIDs {
+			byGuild[guildID] = append(byGuild[guildID], definition)
+		}
+	}
+	b.commandsMu.RUnlock()
+
+	if applicationID == "" {
+		return fmt.Errorf("application id not known yet, wait for the bot to be ready")
+	}
+
+	if len(global) > 0 {
+		if _, err := b.restClient.BulkOverwriteGlobalApplicationCommands(ctx, applicationID, global); err != nil {
+			return fmt.Errorf("failed to sync global commands: %w", err)
+		}
+	}
+
+	for guildID, commands := range byGuild {
+		if _, err := b.restClient.BulkOverwriteGuildApplicationCommands(ctx, applicationID, guildID, commands); err != nil {
+			return fmt.Errorf("failed to sync commands for guild %s: %w", guildID, err)
+		}
+	}
+
+	return nil
+}
+
+// syncCommandsOnReady syncs the slash commands in the background on the first READY, so that the events aren't held
+// up by the requests. If it fails, it's retried on the next READY.
+func (b *Bot) syncCommandsOnReady(ctx context.Context) {
+	b.commandsMu.Lock()
+	if b.commandsSynced || len(b.slashCommands) == 0 {
+		b.commandsMu.Unlock()
+		return
+	}
+	b.commandsSynced = true
+	b.commandsMu.Unlock()
+
+	go func() {
+		if err := b.SyncCommandsContext(ctx); err != nil {
+			b.logger.Error("Failed to sync slash commands.", "error", err)
+
+			b.commandsMu.Lock()
+			b.commandsSynced = false
+			b.commandsMu.Unlock()
+		}
+	}()
+}
+
+// cloneCommandOptions deep copies options, which are otherwise shared with the registered definitions.
+func cloneCommandOptions(options []ApplicationCommandOption) []ApplicationCommandOption {
+	if options == nil {
+		return nil
+	}
+
+	cloned := make([]ApplicationCommandOption, len(options))
+	for i, option := range options {
+		option.Choices = slices.Clone(option.Choices)
+		option.Options = cloneCommandOptions(option.Options)
+		option.ChannelTypes = slices.Clone(option.ChannelTypes)
+		option.MinValue = clonePointer(option.MinValue)
+		option.MaxValue = clonePointer(option.MaxValue)
+		option.MinLength = clonePointer(option.MinLength)
+		option.MaxLength = clonePointer(option.MaxLength)
+		cloned[i] = option
+	}
+
+	return cloned
+}
+
+// clonePointer returns a pointer to a copy of *p, or nil if p is nil.
+func clonePointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+
+	v := *p
+	return &v
+}
+
+// handleInteraction routes an incoming interaction to its registered handler.
+func (b *Bot) handleInteraction(ctx context.Context, shard *Shard, interaction Interaction) error {
+	// Interactions from DMs have no guild, but the handler still needs the rest client to respond.
+	var guildID string
+	if interaction.GuildID != nil {
+		guildID = *interaction.GuildID
+	}
+
+	fetcher := b.fetcherFor(ctx, shard, guildID)
+
+	switch interaction.Type {
+	case MessageInteractionApplicationCommand:
+		data, err := interaction.CommandData()
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal command data: %w", err)
+		}
+
+		b.commandsMu.RLock()
+		command, ok := b.slashCommands[data.Name]
+		b.commandsMu.RUnlock()
+		if !ok {
+			b.logger.Warn("Received unknown application command.", "name", data.Name)
+			return nil
+		}
+
+		if err := command.handler(fetcher, interaction, newSlashCommandOptions(data)); err != nil {
+			return fmt.Errorf("slash command %s failed: %w", data.Name, err)
+		}
+	case MessageInteractionApplicationAutocomplete:
+		return b.handleAutocomplete(fetcher, interaction)
+	case MessageInteractionMessageComponent:
+		return b.handleComponentInteraction(fetcher, interaction)
+	case MessageInteractionModalSubmit:
+		return b.handleModalSubmit(fetcher, interaction)
+	default:
+		b.logger.Info("Got unhandled interaction type.", "type", interaction.Type)
+	}
+
+	return nil
+}
+
+// SlashCommandOptions are the option values a user invoked a slash command with.
+type SlashCommandOptions struct {
+	// Subcommand is the path to the invoked subcommand, e.g. ["group", "subcommand"].
+	// It is empty if the command has no subcommands.
+	Subcommand []string
+
+	options  map[string]ApplicationCommandInteractionDataOption
+	resolved *MessageResolvedData
+}
+
+func newSlashCommandOptions(data ApplicationCommandData) SlashCommandOptions {
+	opts := SlashCommandOptions{
+		options:  make(map[string]ApplicationCommandInteractionDataOption),
+		resolved: data.Resolved,
+	}
+
+	options := data.Options
+	for len(options) == 1 && (options[0].Type == ApplicationCommandOptionTypeSubCommand ||
+		options[0].Type == ApplicationCommandOptionTypeSubCommandGroup) {
+		opts.Subcommand = append(opts.Subcommand, options[0].Name)
+		options = options[0].Options
+	}
+
+	for _, option := range options {
+		opts.options[option.Name] = option
+	}
+
+	return opts
+}
+
+// Has returns true if the option was provided by the user.
+func (o SlashCommandOptions) Has(name string) bool {
+	_, ok := o.options[name]
+	return ok
+}
+
+func optionValue[T any](o SlashCommandOptions, name string) (T, bool) {
+	var ret T
+	option, ok := o.options[name]
+	if !ok || len(option.Value) == 0 {
+		return ret, false
+	}
+
+	if err := json.Unmarshal(option.Value, &ret); err != nil {
+		return ret, false
+	}
+
+	return ret, true
+}
+
+// String returns the value of a STRING option.
+func (o SlashCommandOptions) String(name string) (string, bool) {
+	return optionValue[string](o, name)
+}
+
+// Int returns the value of an INTEGER option.
+func (o SlashCommandOptions) Int(name string) (int64, bool) {
+	return optionValue[int64](o, name)
+}
+
+// Float returns the value of a NUMBER option.
+func (o SlashCommandOptions) Float(name string) (float64, bool) {
+	return optionValue[float64](o, name)
+}
+
+// Bool returns the value of a BOOLEAN option.
+func (o SlashCommandOptions) Bool(name string) (bool, bool) {
+	return optionValue[bool](o, name)
+}
+
+// ID returns the snowflake of a USER, CHANNEL, ROLE, MENTIONABLE or ATTACHMENT option.
+func (o SlashCommandOptions) ID(name string) (string, bool) {
+	return optionValue[string](o, name)
+}
+
+// User returns the resolved user of a USER or MENTIONABLE option.
+func (o SlashCommandOptions) User(name string) (User, bool) {
+	id, ok := o.ID(name)
+	if !ok || o.resolved == nil {
+		return User{}, false
+	}
+
+	user, ok := o.resolved.Users[id]
+	return user, ok
+}
+
+// Member returns the resolved partial guild member of a USER or MENTIONABLE option.
+// It is only available when the command was invoked in a guild.
+func (o SlashCommandOptions) Member(name string) (GuildMember, bool) {
+	id, ok := o.ID(name)
+	if !ok || o.resolved == nil {
+		return GuildMember{}, false
+	}
+
+	member, ok := o.resolved.Members[id]
+	return member, ok
+}
+
+// Channel returns the resolved partial channel of a CHANNEL option.
+func (o SlashCommandOptions) Channel(name string) (Channel, bool) {
+	id, ok := o.ID(name)
+	if !ok || o.resolved == nil {
+		return Channel{}, false
+	}
+
+	channel, ok := o.resolved.Channels[id]
+	return channel, ok
+}
+
+// Role returns the resolved role of a ROLE or MENTIONABLE option.
+func (o SlashCommandOptions) Role(name string) (Role, bool) {
+	id, ok := o.ID(name)
+	if !ok || o.resolved == nil {
+		return Role{}, false
+	}
+
+	role, ok := o.resolved.Roles[id]
+	return role, ok
+}
+
+// Attachment returns the resolved attachment of an ATTACHMENT option.
+func (o SlashCommandOptions) Attachment(name string) (MessageAttachment, bool) {
+	id, ok := o.ID(name)
+	if !ok || o.resolved == nil {
+		return MessageAttachment{}, false
+	}
+
+	attachment, ok := o.resolved.Attachments[id]
+	return attachment, ok
+}
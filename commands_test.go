@@ -0,0 +1,43 @@
+package godiscord
+
+import "testing"
+
+func TestRegisterSlashCommandCopiesTheDefinition(t *testing.T) {
+	b := newTestBot()
+
+	command := SlashCommand{
+		Name:        "search",
+		Description: "Searches",
+		Options: []ApplicationCommandOption{
+			{
+				Type:        ApplicationCommandOptionTypeString,
+				Name:        "query",
+				Description: "What to search for",
+			},
+		},
+	}
+
+	if err := b.RegisterSlashCommand(command, nil); err != nil {
+		t.Fatalf("failed to register command: %v", err)
+	}
+
+	if err := b.RegisterAutocomplete("search", "query", nil); err != nil {
+		t.Fatalf("failed to register autocomplete: %v", err)
+	}
+
+	if command.Options[0].Autocomplete {
+		t.Errorf("registering autocomplete changed the caller's command")
+	}
+
+	// Changes of the caller's command after registration don't reach the bot's definition.
+	command.Options[0].Name = "changed"
+
+	option := b.slashCommands["search"].definition.Options[0]
+	if !option.Autocomplete {
+		t.Errorf("autocomplete isn't enabled in the registered definition")
+	}
+
+	if option.Name != "query" {
+		t.Errorf("registered option was renamed to %q", option.Name)
+	}
+}
@@ -0,0 +1,186 @@
+package godiscord
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ComponentsBuilder builds the action rows of a message, validating them against discord's limits.
+//
+//	components, err := godiscord.NewComponents().
+//		AddButtons(
+//			godiscord.Button{Style: godiscord.ButtonStyleSuccess, Label: &yes, CustomID: &confirmID},
+//			godiscord.Button{Style: godiscord.ButtonStyleDanger, Label: &no, CustomID: &cancelID},
+//		).
+//		Build()
+type ComponentsBuilder struct {
+	rows []componentRow
+}
+
+type componentRow struct {
+	buttons    []Button
+	selectMenu *SelectMenu
+}
+
+// NewComponents creates an empty components builder.
+func NewComponents() *ComponentsBuilder {
+	return &ComponentsBuilder{}
+}
+
+// AddButtons adds an action row containing the given buttons, max 5 per row.
+// The type of each button is set automatically.
+func (c *ComponentsBuilder) AddButtons(buttons ...Button) *ComponentsBuilder {
+	c.rows = append(c.rows, componentRow{buttons: buttons})
+	return c
+}
+
+// AddSelectMenu adds an action row containing the given select menu.
+// A select menu always takes up a whole row. The type must be set to one of the select component types,
+// and defaults to ComponentTypeStringSelect if left empty.
+func (c *ComponentsBuilder) AddSelectMenu(menu SelectMenu) *ComponentsBuilder {
+	c.rows = append(c.rows, componentRow{selectMenu: &menu})
+	return c
+}
+
+// Build validates the components and marshals them into action rows,
+// ready to be used as the components of a message.
+func (c *ComponentsBuilder) Build() ([]MessageActionType, error) {
+	if len(c.rows) > 5 {
+		return nil, fmt.Errorf("a message can have at most 5 action rows, got %d", len(c.rows))
+	}
+
+	ret := make([]MessageActionType, 0, len(c.rows))
+	for i, row := range c.rows {
+		components, err := row.build()
+		if err != nil {
+			return nil, fmt.Errorf("invalid action row %d: %w", i, err)
+		}
+
+		ret = append(ret, MessageActionType{
+			Type:       ComponentTypeActionRow,
+			Components: components,
+		})
+	}
+
+	return ret, nil
+}
+
+func (r componentRow) build() ([]json.RawMessage, error) {
+	if r.selectMenu != nil {
+		menu := *r.selectMenu
+		if menu.Type == 0 {
+			menu.Type = ComponentTypeStringSelect
+		}
+
+		if err := validateSelectMenu(menu); err != nil {
+			return nil, fmt.Errorf("invalid select menu %q: %w", menu.CustomID, err)
+		}
+
+		bs, err := json.Marshal(menu)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal select menu: %w", err)
+		}
+
+		return []json.RawMessage{bs}, nil
+	}
+
+	if len(r.buttons) < 1 || len(r.buttons) > 5 {
+		return nil, fmt.Errorf("an action row must have between 1 and 5 buttons, got %d", len(r.buttons))
+	}
+
+	var ret []json.RawMessage
+	for i, button := range r.buttons {
+		button.Type = ComponentTypeButton
+		if err := validateButton(button); err != nil {
+			return nil, fmt.Errorf("invalid button %d: %w", i, err)
+		}
+
+		bs, err := json.Marshal(button)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal button: %w", err)
+		}
+
+		ret = append(ret, bs)
+	}
+
+	return ret, nil
+}
+
+func validateButton(button Button) error {
+	if button.Style < ButtonStylePrimary || button.Style > ButtonStyleLink {
+		return fmt.Errorf("unknown button style %d", button.Style)
+	}
+
+	if button.Label == nil && button.Emoji == nil {
+		return fmt.Errorf("a button must have a label or an emoji")
+	}
+
+	if button.Label != nil && len([]rune(*button.Label)) > 80 {
+		return fmt.Errorf("label can be at most 80 characters")
+	}
+
+	if button.Style == ButtonStyleLink {
+		if button.URL == nil || button.CustomID != nil {
+			return fmt.Errorf("a link button must have a url and no custom_id")
+		}
+
+		return nil
+	}
+
+	if button.CustomID == nil || button.URL != nil {
+		return fmt.Errorf("a non-link button must have a custom_id and no url")
+	}
+
+	if l := len(*button.CustomID); l < 1 || l > 100 {
+		return fmt.Errorf("custom_id must be between 1 and 100 characters")
+	}
+
+	return nil
+}
+
+func validateSelectMenu(menu SelectMenu) error {
+	switch menu.Type {
+	case ComponentTypeStringSelect:
+		if len(menu.Options) < 1 || len(menu.Options) > 25 {
+			return fmt.Errorf("a string select must have between 1 and 25 options, got %d", len(menu.Options))
+		}
+
+		for i, option := range menu.Options {
+			if option == nil {
+				return fmt.Errorf("option %d is nil", i)
+			}
+
+			if l := len([]rune(option.Label)); l < 1 || l > 100 {
+				return fmt.Errorf("option label must be between 1 and 100 characters")
+			}
+
+			if l := len([]rune(option.Value)); l < 1 || l > 100 {
+				return fmt.Errorf("option value must be between 1 and 100 characters")
+			}
+		}
+	case ComponentTypeUserSelect, ComponentTypeRoleSelect, ComponentTypeMentionableSelect, ComponentTypeChannelSelect:
+		if len(menu.Options) > 0 {
+			return fmt.Errorf("options are only available for string selects")
+		}
+	default:
+		return fmt.Errorf("component type %d is not a select menu", menu.Type)
+	}
+
+	if l := len(menu.CustomID); l < 1 || l > 100 {
+		return fmt.Errorf("custom_id must be between 1 and 100 characters")
+	}
+
+	if menu.Placeholder != nil && len([]rune(*menu.Placeholder)) > 150 {
+		return fmt.Errorf("placeholder can be at most 150 characters")
+	}
+
+	if menu.MinValues != nil && (*menu.MinValues < 0 || *menu.MinValues > 25) {
+		return fmt.Errorf("min_values must be between 0 and 25")
+	}
+
+	if menu.MaxValues != nil && (*menu.MaxValues < 1 || *menu.MaxValues > 25) {
+		return fmt.Errorf("max_values must be between 1 and 25")
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package godiscord
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestComponentsBuilder(t *testing.T) {
+	label := "Yes"
+	customID := "confirm"
+	url := "https://discord.com"
+	longLabel := strings.Repeat("a", 81)
+	longCustomID := strings.Repeat("a", 101)
+	zero := 0
+	tooMany := 26
+
+	button := Button{Style: ButtonStyleSuccess, Label: &label, CustomID: &customID}
+	option := &SelectOption{Label: "One", Value: "1"}
+
+	buttons := func(n int) []Button {
+		ret := make([]Button, n)
+		for i := range ret {
+			ret[i] = button
+		}
+		return ret
+	}
+
+	options := func(n int) []*SelectOption {
+		ret := make([]*SelectOption, n)
+		for i := range ret {
+			ret[i] = option
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name  string
+		build func(*ComponentsBuilder)
+		valid bool
+	}{
+		{"button", func(c *ComponentsBuilder) { c.AddButtons(button) }, true},
+		{"5 buttons", func(c *ComponentsBuilder) { c.AddButtons(buttons(5)...) }, true},
+		{"6 buttons", func(c *ComponentsBuilder) { c.AddButtons(buttons(6)...) }, false},
+		{"empty row", func(c *ComponentsBuilder) { c.AddButtons() }, false},
+		{"5 rows", func(c *ComponentsBuilder) {
+			for i := 0; i < 5; i++ {
+				c.AddButtons(button)
+			}
+		}, true},
+		{"6 rows", func(c *ComponentsBuilder) {
+			for i := 0; i < 6; i++ {
+				c.AddButtons(button)
+			}
+		}, false},
+		{"unknown style", func(c *ComponentsBuilder) { c.AddButtons(Button{Style: 7, Label: &label, CustomID: &customID}) }, false},
+		{"no label or emoji", func(c *ComponentsBuilder) { c.AddButtons(Button{Style: ButtonStylePrimary, CustomID: &customID}) }, false},
+		{"long label", func(c *ComponentsBuilder) {
+			c.AddButtons(Button{Style: ButtonStylePrimary, Label: &longLabel, CustomID: &customID})
+		}, false},
+		{"link", func(c *ComponentsBuilder) { c.AddButtons(Button{Style: ButtonStyleLink, Label: &label, URL: &url}) }, true},
+		{"link with custom_id", func(c *ComponentsBuilder) {
+			c.AddButtons(Button{Style: ButtonStyleLink, Label: &label, URL: &url, CustomID: &customID})
+		}, false},
+		{"button with url", func(c *ComponentsBuilder) {
+			c.AddButtons(Button{Style: ButtonStylePrimary, Label: &label, URL: &url, CustomID: &customID})
+		}, false},
+		{"long custom_id", func(c *ComponentsBuilder) {
+			c.AddButtons(Button{Style: ButtonStylePrimary, Label: &label, CustomID: &longCustomID})
+		}, false},
+		{"string select", func(c *ComponentsBuilder) { c.AddSelectMenu(SelectMenu{CustomID: "menu", Options: options(25)}) }, true},
+		{"string select without options", func(c *ComponentsBuilder) { c.AddSelectMenu(SelectMenu{CustomID: "menu"}) }, false},
+		{"26 options", func(c *ComponentsBuilder) { c.AddSelectMenu(SelectMenu{CustomID: "menu", Options: options(26)}) }, false},
+		{"nil option", func(c *ComponentsBuilder) {
+			c.AddSelectMenu(SelectMenu{CustomID: "menu", Options: []*SelectOption{option, nil}})
+		}, false},
+		{"option without value", func(c *ComponentsBuilder) {
+			c.AddSelectMenu(SelectMenu{CustomID: "menu", Options: []*SelectOption{{Label: "One"}}})
+		}, false},
+		{"user select", func(c *ComponentsBuilder) {
+			c.AddSelectMenu(SelectMenu{Type: ComponentTypeUserSelect, CustomID: "menu", MinValues: &zero})
+		}, true},
+		{"user select with options", func(c *ComponentsBuilder) {
+			c.AddSelectMenu(SelectMenu{Type: ComponentTypeUserSelect, CustomID: "menu", Options: options(1)})
+		}, false},
+		{"not a select menu", func(c *ComponentsBuilder) { c.AddSelectMenu(SelectMenu{Type: ComponentTypeButton, CustomID: "menu"}) }, false},
+		{"select without custom_id", func(c *ComponentsBuilder) { c.AddSelectMenu(SelectMenu{Options: options(1)}) }, false},
+		{"too many values", func(c *ComponentsBuilder) {
+			c.AddSelectMenu(SelectMenu{CustomID: "menu", Options: options(1), MaxValues: &tooMany})
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewComponents()
+			tt.build(c)
+
+			rows, err := c.Build()
+			if !tt.valid {
+				if err == nil {
+					t.Errorf("got no error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to build: %v", err)
+			}
+
+			for _, row := range rows {
+				if row.Type != ComponentTypeActionRow {
+					t.Errorf("got row of type %d", row.Type)
+				}
+
+				for _, component := range row.Components {
+					var v struct {
+						Type ComponentType `json:"type"`
+					}
+					if err := json.Unmarshal(component, &v); err != nil || v.Type == 0 {
+						t.Errorf("component %s has no type", component)
+					}
+				}
+			}
+		})
+	}
+}
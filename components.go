@@ -0,0 +1,236 @@
+package godiscord
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ComponentFunc is called when a user interacts with a message component whose custom_id matches a registered pattern.
+type ComponentFunc func(*Fetcher, ComponentInteraction) error
+
+// MessageComponentData is the data sent with a message component interaction.
+type MessageComponentData struct {
+	CustomID      string               `json:"custom_id"`          // Developer-defined identifier for the component.
+	ComponentType ComponentType        `json:"component_type"`     // Type of the component.
+	Values        []string             `json:"values,omitempty"`   // Values the user selected in a select menu component.
+	Resolved      *MessageResolvedData `json:"resolved,omitempty"` // Resolved entities from selected options, for user, role, mentionable and channel selects.
+}
+
+// ComponentInteraction is a message component interaction, routed by its custom_id.
+// The message the component was attached to is available as Message.
+type ComponentInteraction struct {
+	Interaction
+
+	Data MessageComponentData
+
+	// Params holds the values of the {param} placeholders in the pattern the custom_id matched.
+	Params map[string]string
+}
+
+// SelectedUsers returns the users selected in a user or mentionable select.
+func (c ComponentInteraction) SelectedUsers() (users []User) {
+	if c.Data.Resolved == nil {
+		return nil
+	}
+
+	for _, id := range c.Data.Values {
+		if user, ok := c.Data.Resolved.Users[id]; ok {
+			users = append(users, user)
+		}
+	}
+
+	return users
+}
+
+// SelectedRoles returns the roles selected in a role or mentionable select.
+func (c ComponentInteraction) SelectedRoles() (roles []Role) {
+	if c.Data.Resolved == nil {
+		return nil
+	}
+
+	for _, id := range c.Data.Values {
+		if role, ok := c.Data.Resolved.Roles[id]; ok {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+// SelectedChannels returns the partial channels selected in a channel select.
+func (c ComponentInteraction) SelectedChannels() (channels []Channel) {
+	if c.Data.Resolved == nil {
+		return nil
+	}
+
+	for _, id := range c.Data.Values {
+		if channel, ok := c.Data.Resolved.Channels[id]; ok {
+			channels = append(channels, channel)
+		}
+	}
+
+	return channels
+}
+
+// RegisterComponentHandler registers a handler for message component interactions.
+// customIDPattern is matched against the custom_id of the component, and can be either:
+//   - an exact custom_id, e.g. "confirm".
+//   - a prefix ending with *, e.g. "poll:*".
+//   - a pattern with {param} placeholders, e.g. "vote:{poll}:{choice}". The values are available in ComponentInteraction.Params.
+//
+// Exact matches take precedence, otherwise patterns are tried in the order they were registered.
+// Handlers can be registered at any time, also while the bot is running.
+func (b *Bot) RegisterComponentHandler(customIDPattern string, handler ComponentFunc) error {
+	return b.componentRoutes.add(customIDPattern, handler)
+}
+
+func (b *Bot) handleComponentInteraction(fetcher *Fetcher, interaction Interaction) error {
+	data, err := UnmarshalJSON[MessageComponentData](interaction.Data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal component data: %w", err)
+	}
+
+	handler, params, ok := b.componentRoutes.match(data.CustomID)
+	if !ok {
+		b.logger.Warn("Received component interaction without a handler.", "custom_id", data.CustomID)
+		return nil
+	}
+
+	if err := handler(fetcher, ComponentInteraction{
+		Interaction: interaction,
+		Data:        data,
+		Params:      params,
+	}); err != nil {
+		return fmt.Errorf("component handler of %s failed: %w", data.CustomID, err)
+	}
+
+	return nil
+}
+
+// customIDRouter routes custom_ids to handlers, as used by both message components and modals.
+// Handlers can be added while interactions are being routed.
+type customIDRouter[T any] struct {
+	mu       sync.RWMutex
+	exact    map[string]T
+	patterns []customIDRoute[T]
+}
+
+type customIDRoute[T any] struct {
+	pattern string
+	re      *regexp.Regexp
+	handler T
+}
+
+func newCustomIDRouter[T any]() *customIDRouter[T] {
+	return &customIDRouter[T]{
+		exact: make(map[string]T),
+	}
+}
+
+func (r *customIDRouter[T]) add(pattern string, handler T) error {
+	if pattern == "" || len(pattern) > 100 {
+		return fmt.Errorf("custom_id pattern must be between 1 and 100 characters")
+	}
+
+	isPrefix := strings.HasSuffix(pattern, "*")
+	hasParams := strings.Contains(pattern, "{")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !isPrefix && !hasParams {
+		if _, ok := r.exact[pattern]; ok {
+			return fmt.Errorf("custom_id %q already registered", pattern)
+		}
+
+		r.exact[pattern] = handler
+		return nil
+	}
+
+	re, err := compileCustomIDPattern(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid custom_id pattern %q: %w", pattern, err)
+	}
+
+	for _, route := range r.patterns {
+		if route.pattern == pattern {
+			return fmt.Errorf("custom_id pattern %q already registered", pattern)
+		}
+	}
+
+	r.patterns = append(r.patterns, customIDRoute[T]{
+		pattern: pattern,
+		re:      re,
+		handler: handler,
+	})
+
+	return nil
+}
+
+func (r *customIDRouter[T]) match(customID string) (T, map[string]string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if handler, ok := r.exact[customID]; ok {
+		return handler, map[string]string{}, true
+	}
+
+	for _, route := range r.patterns {
+		matches := route.re.FindStringSubmatch(customID)
+		if matches == nil {
+			continue
+		}
+
+		params := make(map[string]string)
+		for i, name := range route.re.SubexpNames() {
+			if name != "" {
+				params[name] = matches[i]
+			}
+		}
+
+		return route.handler, params, true
+	}
+
+	var zero T
+	return zero, nil, false
+}
+
+// customIDParamRe matches a {param} placeholder of a custom_id pattern.
+var customIDParamRe = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// compileCustomIDPattern converts a custom_id pattern to an anchored regular expression,
+// where a trailing * matches anything and each {param} matches a non-empty value.
+func compileCustomIDPattern(pattern string) (*regexp.Regexp, error) {
+	prefix := strings.HasSuffix(pattern, "*")
+	pattern = strings.TrimSuffix(pattern, "*")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+
+	last := 0
+	for _, loc := range customIDParamRe.FindAllStringSubmatchIndex(pattern, -1) {
+		literal := pattern[last:loc[0]]
+		if strings.ContainsAny(literal, "{}") {
+			return nil, fmt.Errorf("malformed placeholder")
+		}
+
+		sb.WriteString(regexp.QuoteMeta(literal))
+		sb.WriteString(fmt.Sprintf("(?P<%s>.+?)", pattern[loc[2]:loc[3]]))
+		last = loc[1]
+	}
+
+	literal := pattern[last:]
+	if strings.ContainsAny(literal, "{}") {
+		return nil, fmt.Errorf("malformed placeholder")
+	}
+
+	sb.WriteString(regexp.QuoteMeta(literal))
+	if prefix {
+		sb.WriteString(".*")
+	}
+	sb.WriteString("$")
+
+	return regexp.Compile(sb.String())
+}
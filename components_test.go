@@ -0,0 +1,79 @@
+package godiscord
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCustomIDRouterMatch(t *testing.T) {
+	r := newCustomIDRouter[string]()
+	for _, pattern := range []string{
+		"ticket:close",
+		"ticket:{id}",
+		"vote:{poll}:{option}",
+		"page:*",
+		"page:{n}:*",
+		"a.b",
+	} {
+		if err := r.add(pattern, pattern); err != nil {
+			t.Fatalf("failed to add %q: %v", pattern, err)
+		}
+	}
+
+	tests := []struct {
+		customID string
+		handler  string // Empty if nothing matches.
+		params   map[string]string
+	}{
+		// Exact custom_ids take precedence over patterns.
+		{"ticket:close", "ticket:close", map[string]string{}},
+		{"ticket:42", "ticket:{id}", map[string]string{"id": "42"}},
+		{"ticket:", "", nil},
+		{"vote:123:yes", "vote:{poll}:{option}", map[string]string{"poll": "123", "option": "yes"}},
+		{"vote:123", "", nil},
+		// Patterns are tried in the order they were added.
+		{"page:3:next", "page:*", map[string]string{}},
+		{"page:", "page:*", map[string]string{}},
+		// Regexp metacharacters are matched literally.
+		{"a.b", "a.b", map[string]string{}},
+		{"axb", "", nil},
+		{"something", "", nil},
+	}
+
+	for _, tt := range tests {
+		handler, params, ok := r.match(tt.customID)
+		if ok != (tt.handler != "") || handler != tt.handler {
+			t.Errorf("match(%q) = %q, %v, want %q", tt.customID, handler, ok, tt.handler)
+			continue
+		}
+
+		if ok && !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("match(%q) params = %v, want %v", tt.customID, params, tt.params)
+		}
+	}
+}
+
+func TestCustomIDRouterAddRejectsInvalidPatterns(t *testing.T) {
+	r := newCustomIDRouter[string]()
+	if err := r.add("ticket:{id}", ""); err != nil {
+		t.Fatalf("failed to add pattern: %v", err)
+	}
+
+	if err := r.add("ticket:close", ""); err != nil {
+		t.Fatalf("failed to add custom_id: %v", err)
+	}
+
+	for _, pattern := range []string{
+		"",
+		"ticket:{id}",
+		"ticket:close",
+		"ticket:{id",
+		"ticket:{1d}",
+		"ticket:{}",
+		string(make([]byte, 101)),
+	} {
+		if err := r.add(pattern, ""); err == nil {
+			t.Errorf("add(%q) succeeded", pattern)
+		}
+	}
+}
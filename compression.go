@@ -0,0 +1,145 @@
+package godiscord
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"sync"
+)
+
+// zlibSuffix ends every complete message of a zlib-stream compressed gateway connection (a zlib sync flush).
+var zlibSuffix = []byte{0x00, 0x00, 0xff, 0xff}
+
+// zlibStream inflates the frames of a zlib-stream compressed gateway connection.
+// The whole connection shares a single zlib context, so a message can only be inflated after all previous ones,
+// and a message may be split over several frames.
+//
+// compress/flate treats running out of input as a fatal error, so the inflater runs in a goroutine reading from
+// the stream itself, blocking until more input is fed. Once all input of a complete message has been consumed
+// and the inflater asks for more, all of the message has been inflated.
+type zlibStream struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	input   []byte       // Compressed input not yet consumed by the inflater.
+	output  bytes.Buffer // Inflated output of the current message.
+	waiting bool         // Whether the inflater is waiting for more input.
+	closed  bool
+	err     error
+
+	started bool
+}
+
+func newZlibStream() *zlibStream {
+	z := &zlibStream{}
+	z.cond = sync.NewCond(&z.mu)
+	return z
+}
+
+// inflate feeds a frame to the stream. If the frame completes a message, the inflated message is returned,
+// otherwise nil is returned and the rest of the message is expected in the following frames.
+func (z *zlibStream) inflate(frame []byte) ([]byte, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	if z.err != nil {
+		return nil, z.err
+	}
+
+	if !z.started {
+		z.started = true
+		go z.run()
+	}
+
+	z.input = append(z.input, frame...)
+	z.cond.Broadcast()
+
+	if !bytes.HasSuffix(frame, zlibSuffix) {
+		return nil, nil
+	}
+
+	for (len(z.input) > 0 || !z.waiting) && z.err == nil {
+		z.cond.Wait()
+	}
+
+	if z.err != nil {
+		return nil, z.err
+	}
+
+	message := bytes.Clone(z.output.Bytes())
+	z.output.Reset()
+
+	return message, nil
+}
+
+// close stops the inflater goroutine.
+func (z *zlibStream) close() {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	z.closed = true
+	z.cond.Broadcast()
+}
+
+// run inflates the stream until it's closed or corrupt.
+func (z *zlibStream) run() {
+	zr, err := zlib.NewReader(zlibStreamReader{z})
+	if err != nil {
+		z.fail(err)
+		return
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := zr.Read(buf)
+
+		z.mu.Lock()
+		z.output.Write(buf[:n])
+		z.mu.Unlock()
+
+		if err != nil {
+			z.fail(err)
+			return
+		}
+	}
+}
+
+func (z *zlibStream) fail(err error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	if err == io.EOF || z.closed {
+		err = io.ErrClosedPipe
+	}
+
+	z.err = err
+	z.cond.Broadcast()
+}
+
+// zlibStreamReader is the input of the inflater, blocking until input is available.
+type zlibStreamReader struct {
+	z *zlibStream
+}
+
+func (r zlibStreamReader) Read(p []byte) (int, error) {
+	z := r.z
+
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	for len(z.input) == 0 {
+		if z.closed {
+			return 0, io.EOF
+		}
+
+		z.waiting = true
+		z.cond.Broadcast()
+		z.cond.Wait()
+	}
+
+	z.waiting = false
+	n := copy(p, z.input)
+	z.input = z.input[n:]
+
+	return n, nil
+}
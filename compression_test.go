@@ -0,0 +1,55 @@
+package godiscord
+
+import (
+	"bytes"
+	"compress/zlib"
+	"testing"
+)
+
+func TestZlibStreamInflatesLoggedPayloads(t *testing.T) {
+	payloads := loggedPayloads(t)
+
+	// Discord compresses all messages of a connection with a single zlib context, ending each with a sync flush.
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+
+	z := newZlibStream()
+	defer z.close()
+
+	for i, payload := range payloads {
+		if _, err := zw.Write(payload); err != nil {
+			t.Fatalf("failed to compress: %v", err)
+		}
+
+		if err := zw.Flush(); err != nil {
+			t.Fatalf("failed to flush: %v", err)
+		}
+
+		message := bytes.Clone(compressed.Bytes())
+		compressed.Reset()
+
+		// Large messages are split over several frames.
+		frames := [][]byte{message}
+		if i%2 == 1 && len(message) > 8 {
+			frames = [][]byte{message[:len(message)/2], message[len(message)/2:]}
+		}
+
+		var got []byte
+		for j, frame := range frames {
+			inflated, err := z.inflate(frame)
+			if err != nil {
+				t.Fatalf("failed to inflate message %d: %v", i, err)
+			}
+
+			if j < len(frames)-1 && inflated != nil {
+				t.Fatalf("got message %d before its last frame", i)
+			}
+
+			got = inflated
+		}
+
+		if !bytes.Equal(got, payload) {
+			t.Fatalf("message %d inflated to %s, want %s", i, got, payload)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package godiscord
+
+import (
+	"math/rand"
+	"time"
+)
+
+// ConnectionState is the state of the gateway connection of a shard.
+//
+// A shard starts out Disconnected, and goes through Connecting and then Identifying (new session) or Resuming
+// (existing session) until discord confirms the session with READY or RESUMED, after which it's Connected.
+// When the connection is lost, the shard is WaitingToReconnect while backing off, and then starts over from Connecting.
+type ConnectionState int
+
+// Connection states.
+const (
+	ConnectionStateDisconnected ConnectionState = iota
+	ConnectionStateConnecting
+	ConnectionStateIdentifying
+	ConnectionStateResuming
+	ConnectionStateConnected
+	ConnectionStateWaitingToReconnect
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionStateDisconnected:
+		return "disconnected"
+	case ConnectionStateConnecting:
+		return "connecting"
+	case ConnectionStateIdentifying:
+		return "identifying"
+	case ConnectionStateResuming:
+		return "resuming"
+	case ConnectionStateConnected:
+		return "connected"
+	case ConnectionStateWaitingToReconnect:
+		return "waiting to reconnect"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionStateChange is sent to the functions registered with Bot.OnConnectionStateChange.
+type ConnectionStateChange struct {
+	Shard    *Shard          // Shard whose connection changed state.
+	Previous ConnectionState // State before the change.
+	Current  ConnectionState // State after the change.
+	Err      error           // Why the connection was lost, if it was. Nil on a graceful shutdown.
+}
+
+// OnConnectionStateChange registers a function that is called whenever the connection state of a shard changes.
+// It's called synchronously from the shard's connection loop, so it should return quickly.
+func (b *Bot) OnConnectionStateChange(fn func(ConnectionStateChange)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.connectionStateHandlers = append(b.connectionStateHandlers, fn)
+}
+
+// State returns the current connection state of the shard.
+func (s *Shard) State() ConnectionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state
+}
+
+func (s *Shard) setState(state ConnectionState, err error) {
+	s.mu.Lock()
+	previous := s.state
+	s.state = state
+	s.mu.Unlock()
+
+	if previous == state {
+		return
+	}
+
+	s.bot.mu.Lock()
+	handlers := s.bot.connectionStateHandlers
+	s.bot.mu.Unlock()
+
+	change := ConnectionStateChange{
+		Shard:    s,
+		Previous: previous,
+		Current:  state,
+		Err:      err,
+	}
+
+	for _, handler := range handlers {
+		handler(change)
+	}
+}
+
+// Reconnect backoff limits.
+const (
+	reconnectBaseDelay = time.Second
+	reconnectMaxDelay  = 2 * time.Minute
+)
+
+// reconnectDelay returns how long to wait before the given reconnect attempt, using exponential backoff with jitter.
+// The first attempt is made right away, since most disconnects are discord asking for a reconnect.
+func reconnectDelay(attempt int) time.Duration {
+	if attempt <= 0 {
+		return 0
+	}
+
+	delay := reconnectMaxDelay
+	if attempt <= 8 {
+		delay = min(reconnectBaseDelay<<(attempt-1), reconnectMaxDelay)
+	}
+
+	// Wait somewhere between half and all of the delay, so that shards don't reconnect in lockstep.
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// invalidSessionDelay returns how long to wait after an invalid session before reconnecting, a random 1-5 seconds
+// as asked for by discord.
+func invalidSessionDelay() time.Duration {
+	return time.Second + time.Duration(rand.Int63n(int64(4*time.Second)))
+}
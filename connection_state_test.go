@@ -0,0 +1,65 @@
+package godiscord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	if d := reconnectDelay(0); d != 0 {
+		t.Errorf("first attempt waits %v, want it made right away", d)
+	}
+
+	for attempt := 1; attempt <= 20; attempt++ {
+		// The delay doubles per attempt, up to the max.
+		full := reconnectMaxDelay
+		if attempt <= 8 {
+			full = min(reconnectBaseDelay<<(attempt-1), reconnectMaxDelay)
+		}
+
+		for i := 0; i < 100; i++ {
+			if d := reconnectDelay(attempt); d < full/2 || d > full {
+				t.Fatalf("attempt %d waits %v, want between %v and %v", attempt, d, full/2, full)
+			}
+		}
+	}
+
+	if d := reconnectDelay(1); d > time.Second {
+		t.Errorf("second attempt waits %v, want at most a second", d)
+	}
+
+	if d := reconnectDelay(1000); d > reconnectMaxDelay {
+		t.Errorf("delay of attempt 1000 is %v, above the max of %v", d, reconnectMaxDelay)
+	}
+}
+
+func TestReconnectAfterClose(t *testing.T) {
+	tests := []struct {
+		code int
+		want reconnect
+	}{
+		{1000, reconnectResume},
+		{1001, reconnectResume},
+		{CloseEventUnknownError, reconnectResume},
+		{CloseEventUnknownOpCode, reconnectResume},
+		{CloseEventDecodeError, reconnectResume},
+		{CloseEventNotAuthenticated, reconnectResume},
+		{CloseEventAuthenticationFailed, reconnectNone},
+		{CloseEventAlreadyAuthenticated, reconnectResume},
+		{CloseEventInvalidSeq, reconnectIdentify},
+		{CloseEventRateLimited, reconnectResume},
+		{CloseEventSessionTimeout, reconnectIdentify},
+		{CloseEventInvalidShard, reconnectNone},
+		{CloseEventShardingRequired, reconnectNone},
+		{CloseEventInvalidAPIVersion, reconnectNone},
+		{CloseEventInvalidIntents, reconnectNone},
+		{CloseEventDisallowedIntents, reconnectNone},
+		{4999, reconnectResume},
+	}
+
+	for _, tt := range tests {
+		if got := reconnectAfterClose(tt.code); got != tt.want {
+			t.Errorf("reconnectAfterClose(%d) = %d, want %d", tt.code, got, tt.want)
+		}
+	}
+}
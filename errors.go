@@ -0,0 +1,156 @@
+package godiscord
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// JSON error codes returned by discord, see https://discord.com/developers/docs/topics/opcodes-and-status-codes#json.
+// Only the most commonly handled ones are listed here.
+const (
+	ErrorCodeUnknownChannel       = 10003
+	ErrorCodeUnknownGuild         = 10004
+	ErrorCodeUnknownMember        = 10007
+	ErrorCodeUnknownMessage       = 10008
+	ErrorCodeUnknownRole          = 10011
+	ErrorCodeUnknownUser          = 10013
+	ErrorCodeUnknownInteraction   = 10062
+	ErrorCodeInteractionResponded = 40060
+	ErrorCodeMissingAccess        = 50001
+	ErrorCodeCannotSendToUser     = 50007
+	ErrorCodeMissingPermissions   = 50013
+	ErrorCodeInvalidFormBody      = 50035
+)
+
+// APIError is returned when discord responds to a REST request with a non-2xx status code.
+// Use errors.As to inspect it, or one of the helpers like IsNotFound.
+type APIError struct {
+	StatusCode int    // HTTP status code of the response.
+	Method     string // HTTP method of the request.
+	Path       string // Path of the request, e.g. /channels/1234/messages. Tokens are replaced by {token}.
+	Route      string // Path of the request with ids replaced by placeholders, e.g. /channels/{channel_id}/messages.
+
+	Code    int    `json:"code"`    // JSON error code, see the ErrorCode constants.
+	Message string `json:"message"` // Human readable error message.
+
+	// Errors holds the field level errors, e.g. for an invalid form body. Nil if there are none.
+	Errors *FieldErrors `json:"errors,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "discord api error: %s %s: %d %s", e.Method, e.Route, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Code != 0 || e.Message != "" {
+		fmt.Fprintf(&sb, ": %d: %s", e.Code, e.Message)
+	}
+
+	if e.Errors != nil {
+		flattened := e.Errors.Flatten()
+		fields := make([]string, 0, len(flattened))
+		for field := range flattened {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			for _, fieldErr := range flattened[field] {
+				fmt.Fprintf(&sb, "; %s: %s", field, fieldErr.Message)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// FieldError is a single validation error of a field.
+type FieldError struct {
+	Code    string `json:"code"`    // Error code, e.g. BASE_TYPE_REQUIRED.
+	Message string `json:"message"` // Human readable error message.
+}
+
+// FieldErrors is the tree of field level errors discord returns, mirroring the structure of the request body.
+// Array indices are keys like any other field.
+type FieldErrors struct {
+	Errors []FieldError            // Errors of the field at this level of the tree.
+	Fields map[string]*FieldErrors // Errors of the nested fields, keyed by field name or array index.
+}
+
+func (f *FieldErrors) UnmarshalJSON(bs []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(bs, &raw); err != nil {
+		return err
+	}
+
+	for key, value := range raw {
+		if key == "_errors" {
+			if err := json.Unmarshal(value, &f.Errors); err != nil {
+				return fmt.Errorf("failed to unmarshal field errors: %w", err)
+			}
+
+			continue
+		}
+
+		child := &FieldErrors{}
+		if err := json.Unmarshal(value, child); err != nil {
+			return err
+		}
+
+		if f.Fields == nil {
+			f.Fields = make(map[string]*FieldErrors)
+		}
+		f.Fields[key] = child
+	}
+
+	return nil
+}
+
+// Flatten returns the errors keyed by the dotted path of their field, e.g. "embeds.0.title".
+func (f *FieldErrors) Flatten() map[string][]FieldError {
+	ret := make(map[string][]FieldError)
+	f.flatten("", ret)
+	return ret
+}
+
+func (f *FieldErrors) flatten(path string, ret map[string][]FieldError) {
+	if len(f.Errors) > 0 {
+		ret[path] = append(ret[path], f.Errors...)
+	}
+
+	for key, child := range f.Fields {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+
+		child.flatten(childPath, ret)
+	}
+}
+
+// IsNotFound returns true if err is an APIError with status 404, e.g. for an unknown channel or message.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsMissingPermissions returns true if err is an APIError caused by the bot lacking permissions or access.
+func IsMissingPermissions(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && (apiErr.Code == ErrorCodeMissingPermissions || apiErr.Code == ErrorCodeMissingAccess)
+}
+
+// IsRateLimited returns true if err is an APIError with status 429.
+// Rate limited requests are retried automatically, so this only happens once the retries are exhausted.
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// HasErrorCode returns true if err is an APIError with the given JSON error code.
+func HasErrorCode(err error, code int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
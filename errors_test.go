@@ -0,0 +1,125 @@
+package godiscord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// invalidFormBody is the response discord sends to a message with an invalid embed and component.
+const invalidFormBody = `{
+	"code": 50035,
+	"errors": {
+		"embeds": {
+			"0": {
+				"title": {"_errors": [{"code": "BASE_TYPE_MAX_LENGTH", "message": "Must be 256 or fewer in length."}]},
+				"fields": {
+					"1": {
+						"name": {"_errors": [{"code": "BASE_TYPE_REQUIRED", "message": "This field is required"}]}
+					}
+				}
+			}
+		},
+		"components": {
+			"_errors": [{"code": "BASE_TYPE_BAD_LENGTH", "message": "Must be between 1 and 5 in length."}]
+		}
+	},
+	"message": "Invalid Form Body"
+}`
+
+func TestAPIErrorDecodesInvalidFormBody(t *testing.T) {
+	c := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, invalidFormBody)
+	})
+
+	_, err := c.MessageSend(context.Background(), "123456789012345678", MessageCreateRequest{Content: "hello"})
+
+	var apiErr *APIError
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &apiErr) {
+		t.Fatalf("got error %v, want an api error", err)
+	}
+
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Code != ErrorCodeInvalidFormBody || apiErr.Message != "Invalid Form Body" {
+		t.Errorf("got %d %d %q", apiErr.StatusCode, apiErr.Code, apiErr.Message)
+	}
+
+	if apiErr.Path != "/channels/123456789012345678/messages" || apiErr.Route != "/channels/{channel_id}/messages" {
+		t.Errorf("got path %q and route %q", apiErr.Path, apiErr.Route)
+	}
+
+	if apiErr.Errors == nil {
+		t.Fatalf("field errors weren't decoded")
+	}
+
+	title := apiErr.Errors.Fields["embeds"].Fields["0"].Fields["title"]
+	if title == nil || len(title.Errors) != 1 || title.Errors[0].Code != "BASE_TYPE_MAX_LENGTH" {
+		t.Errorf("got title errors %+v", title)
+	}
+
+	want := map[string][]FieldError{
+		"embeds.0.title":         {{Code: "BASE_TYPE_MAX_LENGTH", Message: "Must be 256 or fewer in length."}},
+		"embeds.0.fields.1.name": {{Code: "BASE_TYPE_REQUIRED", Message: "This field is required"}},
+		"components":             {{Code: "BASE_TYPE_BAD_LENGTH", Message: "Must be between 1 and 5 in length."}},
+	}
+	if got := apiErr.Errors.Flatten(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got flattened errors %v, want %v", got, want)
+	}
+
+	// The fields are listed in order.
+	msg := err.Error()
+	if i, j := strings.Index(msg, "components:"), strings.Index(msg, "embeds.0.title:"); i < 0 || j < 0 || i > j {
+		t.Errorf("fields missing from error %q", msg)
+	}
+
+	if !HasErrorCode(err, ErrorCodeInvalidFormBody) || IsNotFound(err) || IsMissingPermissions(err) || IsRateLimited(err) {
+		t.Errorf("error %v classified wrongly", err)
+	}
+}
+
+func TestAPIErrorHelpers(t *testing.T) {
+	tests := []struct {
+		status             int
+		body               string
+		notFound           bool
+		missingPermissions bool
+	}{
+		{http.StatusNotFound, `{"message": "Unknown Channel", "code": 10003}`, true, false},
+		{http.StatusForbidden, `{"message": "Missing Permissions", "code": 50013}`, false, true},
+		{http.StatusForbidden, `{"message": "Missing Access", "code": 50001}`, false, true},
+		{http.StatusForbidden, `{"message": "Cannot send messages to this user", "code": 50007}`, false, false},
+		// Error responses of proxies have no JSON body.
+		{http.StatusBadGateway, `<html>Bad Gateway</html>`, false, false},
+	}
+
+	for _, tt := range tests {
+		c := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			io.WriteString(w, tt.body)
+		})
+
+		err := c.get(context.Background(), "/channels/123456789012345678", nil)
+		if err == nil {
+			t.Fatalf("got no error for %d %s", tt.status, tt.body)
+		}
+
+		if IsNotFound(err) != tt.notFound || IsMissingPermissions(err) != tt.missingPermissions {
+			t.Errorf("error %v classified wrongly", err)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+			t.Errorf("got error %v, want an api error with status %d", err, tt.status)
+		}
+	}
+
+	if IsNotFound(errors.New("not found")) || HasErrorCode(nil, 0) {
+		t.Errorf("errors other than api errors are classified as api errors")
+	}
+}
@@ -0,0 +1,438 @@
+package godiscord
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// ETF (Erlang External Term Format) tags, see https://www.erlang.org/doc/apps/erts/erl_ext_dist.html.
+const (
+	etfVersion = 131
+
+	etfNewFloat      = 70
+	etfSmallInteger  = 97
+	etfInteger       = 98
+	etfFloat         = 99
+	etfAtom          = 100
+	etfSmallTuple    = 104
+	etfLargeTuple    = 105
+	etfNil           = 106
+	etfString        = 107
+	etfList          = 108
+	etfBinary        = 109
+	etfSmallBig      = 110
+	etfLargeBig      = 111
+	etfSmallAtom     = 115
+	etfMap           = 116
+	etfAtomUTF8      = 118
+	etfSmallAtomUTF8 = 119
+)
+
+// etfMaxSafeInteger is the largest integer a float64 can represent exactly.
+const etfMaxSafeInteger = 1 << 53
+
+// etfToJSON transcodes an ETF encoded gateway payload to JSON, so that it can be decoded like any other payload.
+//
+// Discord sends snowflakes as integers over ETF, while they are strings in JSON. Integers too large to be represented
+// exactly as a float64 (which no other field in the API gets near) are therefore written as JSON strings.
+func etfToJSON(bs []byte) ([]byte, error) {
+	if len(bs) == 0 || bs[0] != etfVersion {
+		return nil, fmt.Errorf("invalid etf version")
+	}
+
+	d := etfDecoder{data: bs[1:]}
+	var out bytes.Buffer
+	if err := d.term(&out); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+type etfDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *etfDecoder) read(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, fmt.Errorf("unexpected end of etf data at offset %d", d.pos)
+	}
+
+	bs := d.data[d.pos : d.pos+n]
+	d.pos += n
+
+	return bs, nil
+}
+
+func (d *etfDecoder) uint8() (int, error) {
+	bs, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(bs[0]), nil
+}
+
+func (d *etfDecoder) uint16() (int, error) {
+	bs, err := d.read(2)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(binary.BigEndian.Uint16(bs)), nil
+}
+
+func (d *etfDecoder) uint32() (int, error) {
+	bs, err := d.read(4)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(binary.BigEndian.Uint32(bs)), nil
+}
+
+// term transcodes a single term to JSON.
+func (d *etfDecoder) term(out *bytes.Buffer) error {
+	tag, err := d.uint8()
+	if err != nil {
+		return err
+	}
+
+	switch tag {
+	case etfSmallInteger:
+		n, err := d.uint8()
+		if err != nil {
+			return err
+		}
+
+		out.WriteString(strconv.Itoa(n))
+	case etfInteger:
+		bs, err := d.read(4)
+		if err != nil {
+			return err
+		}
+
+		out.WriteString(strconv.Itoa(int(int32(binary.BigEndian.Uint32(bs)))))
+	case etfNewFloat:
+		bs, err := d.read(8)
+		if err != nil {
+			return err
+		}
+
+		out.WriteString(strconv.FormatFloat(math.Float64frombits(binary.BigEndian.Uint64(bs)), 'g', -1, 64))
+	case etfFloat:
+		bs, err := d.read(31)
+		if err != nil {
+			return err
+		}
+
+		f, err := strconv.ParseFloat(string(bytes.TrimRight(bs, "\x00")), 64)
+		if err != nil {
+			return fmt.Errorf("invalid etf float: %w", err)
+		}
+
+		out.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	case etfSmallBig, etfLargeBig:
+		var n int
+		if tag == etfSmallBig {
+			n, err = d.uint8()
+		} else {
+			n, err = d.uint32()
+		}
+		if err != nil {
+			return err
+		}
+
+		sign, err := d.uint8()
+		if err != nil {
+			return err
+		}
+
+		digits, err := d.read(n)
+		if err != nil {
+			return err
+		}
+
+		// Digits are stored little endian, big.Int wants them big endian.
+		be := make([]byte, n)
+		for i, digit := range digits {
+			be[n-1-i] = digit
+		}
+
+		v := new(big.Int).SetBytes(be)
+		if sign != 0 {
+			v.Neg(v)
+		}
+
+		if v.CmpAbs(big.NewInt(etfMaxSafeInteger)) >= 0 {
+			out.WriteByte('"')
+			out.WriteString(v.String())
+			out.WriteByte('"')
+		} else {
+			out.WriteString(v.String())
+		}
+	case etfAtom, etfAtomUTF8, etfSmallAtom, etfSmallAtomUTF8:
+		var n int
+		if tag == etfSmallAtom || tag == etfSmallAtomUTF8 {
+			n, err = d.uint8()
+		} else {
+			n, err = d.uint16()
+		}
+		if err != nil {
+			return err
+		}
+
+		atom, err := d.read(n)
+		if err != nil {
+			return err
+		}
+
+		switch string(atom) {
+		case "nil", "null":
+			out.WriteString("null")
+		case "true", "false":
+			out.Write(atom)
+		default:
+			return writeJSONString(out, atom)
+		}
+	case etfBinary:
+		n, err := d.uint32()
+		if err != nil {
+			return err
+		}
+
+		bs, err := d.read(n)
+		if err != nil {
+			return err
+		}
+
+		return writeJSONString(out, bs)
+	case etfString:
+		// Despite the name, this is how erlang sends any list of integers between 0 and 255, e.g. channel types.
+		// Discord sends strings as binaries.
+		n, err := d.uint16()
+		if err != nil {
+			return err
+		}
+
+		bs, err := d.read(n)
+		if err != nil {
+			return err
+		}
+
+		out.WriteByte('[')
+		for i, b := range bs {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+
+			out.WriteString(strconv.Itoa(int(b)))
+		}
+		out.WriteByte(']')
+	case etfNil:
+		out.WriteString("[]")
+	case etfList, etfSmallTuple, etfLargeTuple:
+		var n int
+		if tag == etfSmallTuple {
+			n, err = d.uint8()
+		} else {
+			n, err = d.uint32()
+		}
+		if err != nil {
+			return err
+		}
+
+		out.WriteByte('[')
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+
+			if err := d.term(out); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+
+		if tag == etfList {
+			// Proper lists end with an empty list as tail.
+			tail, err := d.uint8()
+			if err != nil {
+				return err
+			}
+
+			if tail != etfNil {
+				return fmt.Errorf("improper etf lists are not supported")
+			}
+		}
+	case etfMap:
+		n, err := d.uint32()
+		if err != nil {
+			return err
+		}
+
+		out.WriteByte('{')
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+
+			if err := d.key(out); err != nil {
+				return err
+			}
+
+			out.WriteByte(':')
+
+			if err := d.term(out); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported etf tag %d at offset %d", tag, d.pos-1)
+	}
+
+	return nil
+}
+
+// key transcodes a map key, which must be a string in JSON.
+func (d *etfDecoder) key(out *bytes.Buffer) error {
+	var key bytes.Buffer
+	if err := d.term(&key); err != nil {
+		return err
+	}
+
+	if bs := key.Bytes(); len(bs) > 0 && bs[0] == '"' {
+		out.Write(bs)
+		return nil
+	}
+
+	return writeJSONString(out, key.Bytes())
+}
+
+func writeJSONString(out *bytes.Buffer, s []byte) error {
+	bs, err := json.Marshal(string(s))
+	if err != nil {
+		return fmt.Errorf("failed to marshal string: %w", err)
+	}
+
+	out.Write(bs)
+
+	return nil
+}
+
+// jsonToETF encodes a JSON payload as ETF, for sending payloads over an ETF encoded gateway connection.
+func jsonToETF(bs []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to decode json: %w", err)
+	}
+
+	out := bytes.NewBuffer([]byte{etfVersion})
+	if err := encodeETF(out, v); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func encodeETF(out *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case nil:
+		writeETFAtom(out, "nil")
+	case bool:
+		writeETFAtom(out, strconv.FormatBool(v))
+	case string:
+		writeETFBinary(out, v)
+	case json.Number:
+		if n, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			writeETFInteger(out, n)
+			return nil
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return fmt.Errorf("invalid number %q: %w", v, err)
+		}
+
+		out.WriteByte(etfNewFloat)
+		out.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+	case []any:
+		if len(v) == 0 {
+			out.WriteByte(etfNil)
+			return nil
+		}
+
+		out.WriteByte(etfList)
+		out.Write(binary.BigEndian.AppendUint32(nil, uint32(len(v))))
+		for _, elem := range v {
+			if err := encodeETF(out, elem); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(etfNil)
+	case map[string]any:
+		out.WriteByte(etfMap)
+		out.Write(binary.BigEndian.AppendUint32(nil, uint32(len(v))))
+		for key, value := range v {
+			writeETFBinary(out, key)
+			if err := encodeETF(out, value); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported json value %T", v)
+	}
+
+	return nil
+}
+
+func writeETFAtom(out *bytes.Buffer, atom string) {
+	out.WriteByte(etfSmallAtomUTF8)
+	out.WriteByte(byte(len(atom)))
+	out.WriteString(atom)
+}
+
+func writeETFBinary(out *bytes.Buffer, s string) {
+	out.WriteByte(etfBinary)
+	out.Write(binary.BigEndian.AppendUint32(nil, uint32(len(s))))
+	out.WriteString(s)
+}
+
+func writeETFInteger(out *bytes.Buffer, n int64) {
+	switch {
+	case n >= 0 && n <= math.MaxUint8:
+		out.WriteByte(etfSmallInteger)
+		out.WriteByte(byte(n))
+	case n >= math.MinInt32 && n <= math.MaxInt32:
+		out.WriteByte(etfInteger)
+		out.Write(binary.BigEndian.AppendUint32(nil, uint32(int32(n))))
+	default:
+		sign := byte(0)
+		u := uint64(n)
+		if n < 0 {
+			sign = 1
+			u = uint64(-n)
+		}
+
+		var digits []byte
+		for u > 0 {
+			digits = append(digits, byte(u))
+			u >>= 8
+		}
+
+		out.WriteByte(etfSmallBig)
+		out.WriteByte(byte(len(digits)))
+		out.WriteByte(sign)
+		out.Write(digits)
+	}
+}
@@ -0,0 +1,116 @@
+package godiscord
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// loggedPayloads returns the gateway payloads recorded in somelogs.
+func loggedPayloads(t *testing.T) [][]byte {
+	t.Helper()
+
+	f, err := os.Open("somelogs")
+	if err != nil {
+		t.Fatalf("failed to open logs: %v", err)
+	}
+	defer f.Close()
+
+	var payloads [][]byte
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' || !json.Valid(line) {
+			continue
+		}
+
+		payloads = append(payloads, bytes.Clone(line))
+	}
+
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("failed to read logs: %v", err)
+	}
+
+	if len(payloads) == 0 {
+		t.Fatalf("no payloads found in logs")
+	}
+
+	return payloads
+}
+
+func unmarshalAny(t *testing.T, bs []byte) any {
+	t.Helper()
+
+	var v any
+	if err := json.Unmarshal(bs, &v); err != nil {
+		t.Fatalf("invalid json %s: %v", bs, err)
+	}
+
+	return v
+}
+
+func TestETFRoundTripOfLoggedPayloads(t *testing.T) {
+	for _, payload := range loggedPayloads(t) {
+		etf, err := jsonToETF(payload)
+		if err != nil {
+			t.Fatalf("failed to encode %s: %v", payload, err)
+		}
+
+		got, err := etfToJSON(etf)
+		if err != nil {
+			t.Fatalf("failed to decode %s: %v", payload, err)
+		}
+
+		if want := unmarshalAny(t, payload); !reflect.DeepEqual(unmarshalAny(t, got), want) {
+			t.Errorf("round trip of %s gave %s", payload, got)
+		}
+	}
+}
+
+func TestETFToJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		etf  []byte
+		want string
+	}{
+		{
+			name: "string ext is a list of integers",
+			// #{<<"channel_types">> => [0, 5]}
+			etf:  []byte{131, etfMap, 0, 0, 0, 1, etfBinary, 0, 0, 0, 13, 'c', 'h', 'a', 'n', 'n', 'e', 'l', '_', 't', 'y', 'p', 'e', 's', etfString, 0, 2, 0, 5},
+			want: `{"channel_types":[0,5]}`,
+		},
+		{
+			name: "snowflake",
+			// 770330465223442464, too large for a float64.
+			etf:  []byte{131, etfSmallBig, 8, 0, 0x20, 0x00, 0x4e, 0x0b, 0x7a, 0xc3, 0xb0, 0x0a},
+			want: `"770330465223442464"`,
+		},
+		{
+			name: "atoms",
+			etf:  []byte{131, etfList, 0, 0, 0, 3, etfSmallAtomUTF8, 3, 'n', 'i', 'l', etfSmallAtomUTF8, 4, 't', 'r', 'u', 'e', etfSmallAtomUTF8, 5, 'i', 'd', 'l', 'e', 's', etfNil},
+			want: `[null,true,"idles"]`,
+		},
+		{
+			name: "negative integer",
+			etf:  []byte{131, etfInteger, 0xff, 0xff, 0xff, 0xfe},
+			want: `-2`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := etfToJSON(tt.etf)
+			if err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package godiscord
+
+import "sync"
+
+// eventWorker runs the handlers of the events received by a shard, so that the shard keeps reading while they run.
+//
+// The events of a shard are handled one at a time, in the order they were received, while the shards handle theirs
+// in parallel. The queue is unbounded, since the shard must never wait for the handlers: a handler may itself be
+// waiting for events, e.g. the member chunks of Fetcher.RequestMembers. Handlers that can't wait for the queue, such
+// as those of interactions, are started right away instead.
+type eventWorker struct {
+	mu      sync.Mutex
+	pending []func()
+	started sync.WaitGroup // Handlers started outside of the queue.
+
+	wake     chan struct{} // Signaled when a handler is queued.
+	stopped  chan struct{} // Closed with mu held.
+	stopOnce sync.Once
+	done     chan struct{} // Closed once run has returned.
+}
+
+func newEventWorker() *eventWorker {
+	return &eventWorker{
+		wake:    make(chan struct{}, 1),
+		stopped: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// run runs the queued handlers until the worker is stopped.
+func (w *eventWorker) run() {
+	defer close(w.done)
+
+	for {
+		select {
+		case <-w.stopped:
+			return
+		default:
+		}
+
+		w.mu.Lock()
+		if len(w.pending) == 0 {
+			w.mu.Unlock()
+
+			select {
+			case <-w.wake:
+			case <-w.stopped:
+				return
+			}
+
+			continue
+		}
+
+		handler := w.pending[0]
+		w.pending[0] = nil
+		w.pending = w.pending[1:]
+		w.mu.Unlock()
+
+		handler()
+	}
+}
+
+// enqueue queues a handler, without waiting for it to run.
+func (w *eventWorker) enqueue(handler func()) {
+	w.mu.Lock()
+	w.pending = append(w.pending, handler)
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// start runs a handler in a goroutine of its own right away, rather than after the queued ones.
+// It does nothing once the worker is stopped.
+func (w *eventWorker) start(handler func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	select {
+	case <-w.stopped:
+		return
+	default:
+	}
+
+	w.started.Add(1)
+	go func() {
+		defer w.started.Done()
+		handler()
+	}()
+}
+
+// stop waits for the running handlers to return. The handlers still queued are dropped.
+func (w *eventWorker) stop() {
+	w.stopOnce.Do(func() {
+		w.mu.Lock()
+		close(w.stopped)
+		w.mu.Unlock()
+	})
+
+	<-w.done
+	w.started.Wait()
+}
@@ -0,0 +1,70 @@
+package godiscord
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventWorkerRunsQueuedHandlersInOrder(t *testing.T) {
+	w := newEventWorker()
+	go w.run()
+	defer w.stop()
+
+	got := make(chan int, 100)
+	for i := 0; i < 100; i++ {
+		i := i
+		w.enqueue(func() {
+			got <- i
+		})
+	}
+
+	for want := 0; want < 100; want++ {
+		select {
+		case i := <-got:
+			if i != want {
+				t.Fatalf("handler %d ran before %d", i, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("handler %d didn't run", want)
+		}
+	}
+}
+
+func TestEventWorkerStartsHandlersWithoutWaitingForTheQueue(t *testing.T) {
+	w := newEventWorker()
+	go w.run()
+
+	release := make(chan struct{})
+	w.enqueue(func() {
+		<-release
+	})
+
+	started := make(chan struct{})
+	w.start(func() {
+		close(started)
+	})
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatalf("handler waited for the queued one")
+	}
+	close(release)
+
+	// Stopping waits for the started handlers.
+	var finished atomic.Bool
+	w.start(func() {
+		time.Sleep(50 * time.Millisecond)
+		finished.Store(true)
+	})
+	w.stop()
+
+	if !finished.Load() {
+		t.Errorf("stop returned before the started handler")
+	}
+
+	w.start(func() {
+		t.Errorf("handler started after stopping")
+	})
+}
@@ -7,7 +7,7 @@ const (
 	OpCodeHeartbeat           = 1
 	OpCodeIdentity            = 2
 	OpCodePresenceUpdate      = 3
-	OpCodeVoiceStateUpdate    = 5
+	OpCodeVoiceStateUpdate    = 4
 	OpCodeResume              = 6
 	OpCodeReconnect           = 7
 	OpCodeRequestGuildMembers = 8
@@ -34,6 +34,12 @@ const (
 	CloseEventDisallowedIntents    = 4014
 )
 
+// sessionInvalidatingCloseEvents are the close codes that can be reconnected from, but only with a new session.
+var sessionInvalidatingCloseEvents = map[int]bool{
+	CloseEventInvalidSeq:     true,
+	CloseEventSessionTimeout: true,
+}
+
 var ResumeableCloseEvents = map[int]bool{
 	CloseEventUnknownError:         true,
 	CloseEventUnknownOpCode:        true,
@@ -58,6 +64,36 @@ type Event struct {
 	Data           *json.RawMessage `json:"d,omitempty"`
 }
 
+// DispatchEvent is a dispatch event that listeners can be registered for.
 type DispatchEvent interface {
-	Name() string
+	// EventName returns the name of the event, e.g. MESSAGE_CREATE.
+	EventName() string
+	guild() string
+}
+
+// decodeEvent decodes the data of a dispatch event.
+func decodeEvent[T DispatchEvent](data json.RawMessage) (DispatchEvent, error) {
+	ev, err := UnmarshalJSON[T](data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ev, nil
+}
+
+type eventHandler interface {
+	run(*Fetcher, any) error
+	name() string
+}
+
+// listenerFunc is an event listener for events of type T.
+type listenerFunc[T DispatchEvent] func(*Fetcher, T) error
+
+func (f listenerFunc[T]) name() string {
+	var ev T
+	return ev.EventName()
+}
+
+func (f listenerFunc[T]) run(fetcher *Fetcher, ev any) error {
+	return f(fetcher, ev.(T))
 }
@@ -0,0 +1,517 @@
+// Code generated by eventgen. DO NOT EDIT.
+
+package godiscord
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func (Ready) EventName() string {
+	return "READY"
+}
+
+func (Resumed) EventName() string {
+	return "RESUMED"
+}
+
+func (ApplicationCommandPermissionsUpdate) EventName() string {
+	return "APPLICATION_COMMAND_PERMISSIONS_UPDATE"
+}
+
+func (AutoModerationRuleCreate) EventName() string {
+	return "AUTO_MODERATION_RULE_CREATE"
+}
+
+func (AutoModerationRuleUpdate) EventName() string {
+	return "AUTO_MODERATION_RULE_UPDATE"
+}
+
+func (AutoModerationRuleDelete) EventName() string {
+	return "AUTO_MODERATION_RULE_DELETE"
+}
+
+func (AutoModerationActionExecution) EventName() string {
+	return "AUTO_MODERATION_ACTION_EXECUTION"
+}
+
+func (ChannelCreate) EventName() string {
+	return "CHANNEL_CREATE"
+}
+
+func (ChannelUpdate) EventName() string {
+	return "CHANNEL_UPDATE"
+}
+
+func (ChannelDelete) EventName() string {
+	return "CHANNEL_DELETE"
+}
+
+func (ChannelPinsUpdate) EventName() string {
+	return "CHANNEL_PINS_UPDATE"
+}
+
+func (ThreadCreate) EventName() string {
+	return "THREAD_CREATE"
+}
+
+func (ThreadUpdate) EventName() string {
+	return "THREAD_UPDATE"
+}
+
+func (ThreadDelete) EventName() string {
+	return "THREAD_DELETE"
+}
+
+func (ThreadListSync) EventName() string {
+	return "THREAD_LIST_SYNC"
+}
+
+func (ThreadMemberUpdate) EventName() string {
+	return "THREAD_MEMBER_UPDATE"
+}
+
+func (ThreadMembersUpdate) EventName() string {
+	return "THREAD_MEMBERS_UPDATE"
+}
+
+func (EntitlementCreate) EventName() string {
+	return "ENTITLEMENT_CREATE"
+}
+
+func (EntitlementUpdate) EventName() string {
+	return "ENTITLEMENT_UPDATE"
+}
+
+func (EntitlementDelete) EventName() string {
+	return "ENTITLEMENT_DELETE"
+}
+
+func (GuildCreate) EventName() string {
+	return "GUILD_CREATE"
+}
+
+func (GuildUpdate) EventName() string {
+	return "GUILD_UPDATE"
+}
+
+func (GuildDelete) EventName() string {
+	return "GUILD_DELETE"
+}
+
+func (GuildAuditLogEntryCreate) EventName() string {
+	return "GUILD_AUDIT_LOG_ENTRY_CREATE"
+}
+
+func (GuildBanAdd) EventName() string {
+	return "GUILD_BAN_ADD"
+}
+
+func (GuildBanRemove) EventName() string {
+	return "GUILD_BAN_REMOVE"
+}
+
+func (GuildEmojisUpdate) EventName() string {
+	return "GUILD_EMOJIS_UPDATE"
+}
+
+func (GuildStickersUpdate) EventName() string {
+	return "GUILD_STICKERS_UPDATE"
+}
+
+func (GuildIntegrationsUpdate) EventName() string {
+	return "GUILD_INTEGRATIONS_UPDATE"
+}
+
+func (GuildMemberAdd) EventName() string {
+	return "GUILD_MEMBER_ADD"
+}
+
+func (GuildMemberUpdate) EventName() string {
+	return "GUILD_MEMBER_UPDATE"
+}
+
+func (GuildMemberRemove) EventName() string {
+	return "GUILD_MEMBER_REMOVE"
+}
+
+func (GuildMembersChunk) EventName() string {
+	return "GUILD_MEMBERS_CHUNK"
+}
+
+func (GuildRoleCreate) EventName() string {
+	return "GUILD_ROLE_CREATE"
+}
+
+func (GuildRoleUpdate) EventName() string {
+	return "GUILD_ROLE_UPDATE"
+}
+
+func (GuildRoleDelete) EventName() string {
+	return "GUILD_ROLE_DELETE"
+}
+
+func (GuildScheduledEventCreate) EventName() string {
+	return "GUILD_SCHEDULED_EVENT_CREATE"
+}
+
+func (GuildScheduledEventUpdate) EventName() string {
+	return "GUILD_SCHEDULED_EVENT_UPDATE"
+}
+
+func (GuildScheduledEventDelete) EventName() string {
+	return "GUILD_SCHEDULED_EVENT_DELETE"
+}
+
+func (GuildScheduledEventUserAdd) EventName() string {
+	return "GUILD_SCHEDULED_EVENT_USER_ADD"
+}
+
+func (GuildScheduledEventUserRemove) EventName() string {
+	return "GUILD_SCHEDULED_EVENT_USER_REMOVE"
+}
+
+func (GuildSoundboardSoundCreate) EventName() string {
+	return "GUILD_SOUNDBOARD_SOUND_CREATE"
+}
+
+func (GuildSoundboardSoundUpdate) EventName() string {
+	return "GUILD_SOUNDBOARD_SOUND_UPDATE"
+}
+
+func (GuildSoundboardSoundDelete) EventName() string {
+	return "GUILD_SOUNDBOARD_SOUND_DELETE"
+}
+
+func (GuildSoundboardSoundsUpdate) EventName() string {
+	return "GUILD_SOUNDBOARD_SOUNDS_UPDATE"
+}
+
+func (SoundboardSounds) EventName() string {
+	return "SOUNDBOARD_SOUNDS"
+}
+
+func (IntegrationCreate) EventName() string {
+	return "INTEGRATION_CREATE"
+}
+
+func (IntegrationUpdate) EventName() string {
+	return "INTEGRATION_UPDATE"
+}
+
+func (IntegrationDelete) EventName() string {
+	return "INTEGRATION_DELETE"
+}
+
+func (InviteCreate) EventName() string {
+	return "INVITE_CREATE"
+}
+
+func (InviteDelete) EventName() string {
+	return "INVITE_DELETE"
+}
+
+func (MessageCreate) EventName() string {
+	return "MESSAGE_CREATE"
+}
+
+func (MessageUpdate) EventName() string {
+	return "MESSAGE_UPDATE"
+}
+
+func (MessageDelete) EventName() string {
+	return "MESSAGE_DELETE"
+}
+
+func (MessageDeleteBulk) EventName() string {
+	return "MESSAGE_DELETE_BULK"
+}
+
+func (MessageReactionAdd) EventName() string {
+	return "MESSAGE_REACTION_ADD"
+}
+
+func (MessageReactionRemove) EventName() string {
+	return "MESSAGE_REACTION_REMOVE"
+}
+
+func (MessageReactionRemoveAll) EventName() string {
+	return "MESSAGE_REACTION_REMOVE_ALL"
+}
+
+func (MessageReactionRemoveEmoji) EventName() string {
+	return "MESSAGE_REACTION_REMOVE_EMOJI"
+}
+
+func (MessagePollVoteAdd) EventName() string {
+	return "MESSAGE_POLL_VOTE_ADD"
+}
+
+func (MessagePollVoteRemove) EventName() string {
+	return "MESSAGE_POLL_VOTE_REMOVE"
+}
+
+func (PresenceUpdate) EventName() string {
+	return "PRESENCE_UPDATE"
+}
+
+func (InteractionCreate) EventName() string {
+	return "INTERACTION_CREATE"
+}
+
+func (StageInstanceCreate) EventName() string {
+	return "STAGE_INSTANCE_CREATE"
+}
+
+func (StageInstanceUpdate) EventName() string {
+	return "STAGE_INSTANCE_UPDATE"
+}
+
+func (StageInstanceDelete) EventName() string {
+	return "STAGE_INSTANCE_DELETE"
+}
+
+func (TypingStart) EventName() string {
+	return "TYPING_START"
+}
+
+func (UserUpdate) EventName() string {
+	return "USER_UPDATE"
+}
+
+func (VoiceStateUpdate) EventName() string {
+	return "VOICE_STATE_UPDATE"
+}
+
+func (VoiceServerUpdate) EventName() string {
+	return "VOICE_SERVER_UPDATE"
+}
+
+func (WebhooksUpdate) EventName() string {
+	return "WEBHOOKS_UPDATE"
+}
+
+// eventDecoders decodes the dispatch events, by event name.
+var eventDecoders = map[string]func(json.RawMessage) (DispatchEvent, error){
+	"READY":                                  decodeEvent[Ready],
+	"RESUMED":                                decodeEvent[Resumed],
+	"APPLICATION_COMMAND_PERMISSIONS_UPDATE": decodeEvent[ApplicationCommandPermissionsUpdate],
+	"AUTO_MODERATION_RULE_CREATE":            decodeEvent[AutoModerationRuleCreate],
+	"AUTO_MODERATION_RULE_UPDATE":            decodeEvent[AutoModerationRuleUpdate],
+	"AUTO_MODERATION_RULE_DELETE":            decodeEvent[AutoModerationRuleDelete],
+	"AUTO_MODERATION_ACTION_EXECUTION":       decodeEvent[AutoModerationActionExecution],
+	"CHANNEL_CREATE":                         decodeEvent[ChannelCreate],
+	"CHANNEL_UPDATE":                         decodeEvent[ChannelUpdate],
+	"CHANNEL_DELETE":                         decodeEvent[ChannelDelete],
+	"CHANNEL_PINS_UPDATE":                    decodeEvent[ChannelPinsUpdate],
+	"THREAD_CREATE":                          decodeEvent[ThreadCreate],
+	"THREAD_UPDATE":                          decodeEvent[ThreadUpdate],
+	"THREAD_DELETE":                          decodeEvent[ThreadDelete],
+	"THREAD_LIST_SYNC":                       decodeEvent[ThreadListSync],
+	"THREAD_MEMBER_UPDATE":                   decodeEvent[ThreadMemberUpdate],
+	"THREAD_MEMBERS_UPDATE":                  decodeEvent[ThreadMembersUpdate],
+	"ENTITLEMENT_CREATE":                     decodeEvent[EntitlementCreate],
+	"ENTITLEMENT_UPDATE":                     decodeEvent[EntitlementUpdate],
+	"ENTITLEMENT_DELETE":                     decodeEvent[EntitlementDelete],
+	"GUILD_CREATE":                           decodeEvent[GuildCreate],
+	"GUILD_UPDATE":                           decodeEvent[GuildUpdate],
+	"GUILD_DELETE":                           decodeEvent[GuildDelete],
+	"GUILD_AUDIT_LOG_ENTRY_CREATE":           decodeEvent[GuildAuditLogEntryCreate],
+	"GUILD_BAN_ADD":                          decodeEvent[GuildBanAdd],
+	"GUILD_BAN_REMOVE":                       decodeEvent[GuildBanRemove],
+	"GUILD_EMOJIS_UPDATE":                    decodeEvent[GuildEmojisUpdate],
+	"GUILD_STICKERS_UPDATE":                  decodeEvent[GuildStickersUpdate],
+	"GUILD_INTEGRATIONS_UPDATE":              decodeEvent[GuildIntegrationsUpdate],
+	"GUILD_MEMBER_ADD":                       decodeEvent[GuildMemberAdd],
+	"GUILD_MEMBER_UPDATE":                    decodeEvent[GuildMemberUpdate],
+	"GUILD_MEMBER_REMOVE":                    decodeEvent[GuildMemberRemove],
+	"GUILD_MEMBERS_CHUNK":                    decodeEvent[GuildMembersChunk],
+	"GUILD_ROLE_CREATE":                      decodeEvent[GuildRoleCreate],
+	"GUILD_ROLE_UPDATE":                      decodeEvent[GuildRoleUpdate],
+	"GUILD_ROLE_DELETE":                      decodeEvent[GuildRoleDelete],
+	"GUILD_SCHEDULED_EVENT_CREATE":           decodeEvent[GuildScheduledEventCreate],
+	"GUILD_SCHEDULED_EVENT_UPDATE":           decodeEvent[GuildScheduledEventUpdate],
+	"GUILD_SCHEDULED_EVENT_DELETE":           decodeEvent[GuildScheduledEventDelete],
+	"GUILD_SCHEDULED_EVENT_USER_ADD":         decodeEvent[GuildScheduledEventUserAdd],
+	"GUILD_SCHEDULED_EVENT_USER_REMOVE":      decodeEvent[GuildScheduledEventUserRemove],
+	"GUILD_SOUNDBOARD_SOUND_CREATE":          decodeEvent[GuildSoundboardSoundCreate],
+	"GUILD_SOUNDBOARD_SOUND_UPDATE":          decodeEvent[GuildSoundboardSoundUpdate],
+	"GUILD_SOUNDBOARD_SOUND_DELETE":          decodeEvent[GuildSoundboardSoundDelete],
+	"GUILD_SOUNDBOARD_SOUNDS_UPDATE":         decodeEvent[GuildSoundboardSoundsUpdate],
+	"SOUNDBOARD_SOUNDS":                      decodeEvent[SoundboardSounds],
+	"INTEGRATION_CREATE":                     decodeEvent[IntegrationCreate],
+	"INTEGRATION_UPDATE":                     decodeEvent[IntegrationUpdate],
+	"INTEGRATION_DELETE":                     decodeEvent[IntegrationDelete],
+	"INVITE_CREATE":                          decodeEvent[InviteCreate],
+	"INVITE_DELETE":                          decodeEvent[InviteDelete],
+	"MESSAGE_CREATE":                         decodeEvent[MessageCreate],
+	"MESSAGE_UPDATE":                         decodeEvent[MessageUpdate],
+	"MESSAGE_DELETE":                         decodeEvent[MessageDelete],
+	"MESSAGE_DELETE_BULK":                    decodeEvent[MessageDeleteBulk],
+	"MESSAGE_REACTION_ADD":                   decodeEvent[MessageReactionAdd],
+	"MESSAGE_REACTION_REMOVE":                decodeEvent[MessageReactionRemove],
+	"MESSAGE_REACTION_REMOVE_ALL":            decodeEvent[MessageReactionRemoveAll],
+	"MESSAGE_REACTION_REMOVE_EMOJI":          decodeEvent[MessageReactionRemoveEmoji],
+	"MESSAGE_POLL_VOTE_ADD":                  decodeEvent[MessagePollVoteAdd],
+	"MESSAGE_POLL_VOTE_REMOVE":               decodeEvent[MessagePollVoteRemove],
+	"PRESENCE_UPDATE":                        decodeEvent[PresenceUpdate],
+	"INTERACTION_CREATE":                     decodeEvent[InteractionCreate],
+	"STAGE_INSTANCE_CREATE":                  decodeEvent[StageInstanceCreate],
+	"STAGE_INSTANCE_UPDATE":                  decodeEvent[StageInstanceUpdate],
+	"STAGE_INSTANCE_DELETE":                  decodeEvent[StageInstanceDelete],
+	"TYPING_START":                           decodeEvent[TypingStart],
+	"USER_UPDATE":                            decodeEvent[UserUpdate],
+	"VOICE_STATE_UPDATE":                     decodeEvent[VoiceStateUpdate],
+	"VOICE_SERVER_UPDATE":                    decodeEvent[VoiceServerUpdate],
+	"WEBHOOKS_UPDATE":                        decodeEvent[WebhooksUpdate],
+}
+
+// eventHandlerFromInterface returns the handler of a listener in the form of func(*Fetcher, <DispatchEvent>) error.
+func eventHandlerFromInterface(iface any) (eventHandler, error) {
+	switch v := iface.(type) {
+	case func(*Fetcher, Ready) error:
+		return listenerFunc[Ready](v), nil
+	case func(*Fetcher, Resumed) error:
+		return listenerFunc[Resumed](v), nil
+	case func(*Fetcher, ApplicationCommandPermissionsUpdate) error:
+		return listenerFunc[ApplicationCommandPermissionsUpdate](v), nil
+	case func(*Fetcher, AutoModerationRuleCreate) error:
+		return listenerFunc[AutoModerationRuleCreate](v), nil
+	case func(*Fetcher, AutoModerationRuleUpdate) error:
+		return listenerFunc[AutoModerationRuleUpdate](v), nil
+	case func(*Fetcher, AutoModerationRuleDelete) error:
+		return listenerFunc[AutoModerationRuleDelete](v), nil
+	case func(*Fetcher, AutoModerationActionExecution) error:
+		return listenerFunc[AutoModerationActionExecution](v), nil
+	case func(*Fetcher, ChannelCreate) error:
+		return listenerFunc[ChannelCreate](v), nil
+	case func(*Fetcher, ChannelUpdate) error:
+		return listenerFunc[ChannelUpdate](v), nil
+	case func(*Fetcher, ChannelDelete) error:
+		return listenerFunc[ChannelDelete](v), nil
+	case func(*Fetcher, ChannelPinsUpdate) error:
+		return listenerFunc[ChannelPinsUpdate](v), nil
+	case func(*Fetcher, ThreadCreate) error:
+		return listenerFunc[ThreadCreate](v), nil
+	case func(*Fetcher, ThreadUpdate) error:
+		return listenerFunc[ThreadUpdate](v), nil
+	case func(*Fetcher, ThreadDelete) error:
+		return listenerFunc[ThreadDelete](v), nil
+	case func(*Fetcher, ThreadListSync) error:
+		return listenerFunc[ThreadListSync](v), nil
+	case func(*Fetcher, ThreadMemberUpdate) error:
+		return listenerFunc[ThreadMemberUpdate](v), nil
+	case func(*Fetcher, ThreadMembersUpdate) error:
+		return listenerFunc[ThreadMembersUpdate](v), nil
+	case func(*Fetcher, EntitlementCreate) error:
+		return listenerFunc[EntitlementCreate](v), nil
+	case func(*Fetcher, EntitlementUpdate) error:
+		return listenerFunc[EntitlementUpdate](v), nil
+	case func(*Fetcher, EntitlementDelete) error:
+		return listenerFunc[EntitlementDelete](v), nil
+	case func(*Fetcher, GuildCreate) error:
+		return listenerFunc[GuildCreate](v), nil
+	case func(*Fetcher, GuildUpdate) error:
+		return listenerFunc[GuildUpdate](v), nil
+	case func(*Fetcher, GuildDelete) error:
+		return listenerFunc[GuildDelete](v), nil
+	case func(*Fetcher, GuildAuditLogEntryCreate) error:
+		return listenerFunc[GuildAuditLogEntryCreate](v), nil
+	case func(*Fetcher, GuildBanAdd) error:
+		return listenerFunc[GuildBanAdd](v), nil
+	case func(*Fetcher, GuildBanRemove) error:
+		return listenerFunc[GuildBanRemove](v), nil
+	case func(*Fetcher, GuildEmojisUpdate) error:
+		return listenerFunc[GuildEmojisUpdate](v), nil
+	case func(*Fetcher, GuildStickersUpdate) error:
+		return listenerFunc[GuildStickersUpdate](v), nil
+	case func(*Fetcher, GuildIntegrationsUpdate) error:
+		return listenerFunc[GuildIntegrationsUpdate](v), nil
+	case func(*Fetcher, GuildMemberAdd) error:
+		return listenerFunc[GuildMemberAdd](v), nil
+	case func(*Fetcher, GuildMemberUpdate) error:
+		return listenerFunc[GuildMemberUpdate](v), nil
+	case func(*Fetcher, GuildMemberRemove) error:
+		return listenerFunc[GuildMemberRemove](v), nil
+	case func(*Fetcher, GuildMembersChunk) error:
+		return listenerFunc[GuildMembersChunk](v), nil
+	case func(*Fetcher, GuildRoleCreate) error:
+		return listenerFunc[GuildRoleCreate](v), nil
+	case func(*Fetcher, GuildRoleUpdate) error:
+		return listenerFunc[GuildRoleUpdate](v), nil
+	case func(*Fetcher, GuildRoleDelete) error:
+		return listenerFunc[GuildRoleDelete](v), nil
+	case func(*Fetcher, GuildScheduledEventCreate) error:
+		return listenerFunc[GuildScheduledEventCreate](v), nil
+	case func(*Fetcher, GuildScheduledEventUpdate) error:
+		return listenerFunc[GuildScheduledEventUpdate](v), nil
+	case func(*Fetcher, GuildScheduledEventDelete) error:
+		return listenerFunc[GuildScheduledEventDelete](v), nil
+	case func(*Fetcher, GuildScheduledEventUserAdd) error:
+		return listenerFunc[GuildScheduledEventUserAdd](v), nil
+	case func(*Fetcher, GuildScheduledEventUserRemove) error:
+		return listenerFunc[GuildScheduledEventUserRemove](v), nil
+	case func(*Fetcher, GuildSoundboardSoundCreate) error:
+		return listenerFunc[GuildSoundboardSoundCreate](v), nil
+	case func(*Fetcher, GuildSoundboardSoundUpdate) error:
+		return listenerFunc[GuildSoundboardSoundUpdate](v), nil
+	case func(*Fetcher, GuildSoundboardSoundDelete) error:
+		return listenerFunc[GuildSoundboardSoundDelete](v), nil
+	case func(*Fetcher, GuildSoundboardSoundsUpdate) error:
+		return listenerFunc[GuildSoundboardSoundsUpdate](v), nil
+	case func(*Fetcher, SoundboardSounds) error:
+		return listenerFunc[SoundboardSounds](v), nil
+	case func(*Fetcher, IntegrationCreate) error:
+		return listenerFunc[IntegrationCreate](v), nil
+	case func(*Fetcher, IntegrationUpdate) error:
+		return listenerFunc[IntegrationUpdate](v), nil
+	case func(*Fetcher, IntegrationDelete) error:
+		return listenerFunc[IntegrationDelete](v), nil
+	case func(*Fetcher, InviteCreate) error:
+		return listenerFunc[InviteCreate](v), nil
+	case func(*Fetcher, InviteDelete) error:
+		return listenerFunc[InviteDelete](v), nil
+	case func(*Fetcher, MessageCreate) error:
+		return listenerFunc[MessageCreate](v), nil
+	case func(*Fetcher, MessageUpdate) error:
+		return listenerFunc[MessageUpdate](v), nil
+	case func(*Fetcher, MessageDelete) error:
+		return listenerFunc[MessageDelete](v), nil
+	case func(*Fetcher, MessageDeleteBulk) error:
+		return listenerFunc[MessageDeleteBulk](v), nil
+	case func(*Fetcher, MessageReactionAdd) error:
+		return listenerFunc[MessageReactionAdd](v), nil
+	case func(*Fetcher, MessageReactionRemove) error:
+		return listenerFunc[MessageReactionRemove](v), nil
+	case func(*Fetcher, MessageReactionRemoveAll) error:
+		return listenerFunc[MessageReactionRemoveAll](v), nil
+	case func(*Fetcher, MessageReactionRemoveEmoji) error:
+		return listenerFunc[MessageReactionRemoveEmoji](v), nil
+	case func(*Fetcher, MessagePollVoteAdd) error:
+		return listenerFunc[MessagePollVoteAdd](v), nil
+	case func(*Fetcher, MessagePollVoteRemove) error:
+		return listenerFunc[MessagePollVoteRemove](v), nil
+	case func(*Fetcher, PresenceUpdate) error:
+		return listenerFunc[PresenceUpdate](v), nil
+	case func(*Fetcher, InteractionCreate) error:
+		return listenerFunc[InteractionCreate](v), nil
+	case func(*Fetcher, StageInstanceCreate) error:
+		return listenerFunc[StageInstanceCreate](v), nil
+	case func(*Fetcher, StageInstanceUpdate) error:
+		return listenerFunc[StageInstanceUpdate](v), nil
+	case func(*Fetcher, StageInstanceDelete) error:
+		return listenerFunc[StageInstanceDelete](v), nil
+	case func(*Fetcher, TypingStart) error:
+		return listenerFunc[TypingStart](v), nil
+	case func(*Fetcher, UserUpdate) error:
+		return listenerFunc[UserUpdate](v), nil
+	case func(*Fetcher, VoiceStateUpdate) error:
+		return listenerFunc[VoiceStateUpdate](v), nil
+	case func(*Fetcher, VoiceServerUpdate) error:
+		return listenerFunc[VoiceServerUpdate](v), nil
+	case func(*Fetcher, WebhooksUpdate) error:
+		return listenerFunc[WebhooksUpdate](v), nil
+	default:
+		return nil, fmt.Errorf("unknown event listener %T", iface)
+	}
+}
@@ -1,9 +1,12 @@
 package godiscord
 
-import (
-	"fmt"
-	"time"
-)
+import "time"
+
+//go:generate go run ./internal/eventgen -in events_receive.go -out events_gen.go
+
+// The dispatch events that listeners can be registered for are marked with a //godiscord:event directive, naming
+// the event. The generator adds the methods making them a DispatchEvent, the decoding of them, and the registration
+// of listeners for them.
 
 type guildEvent interface {
 	guild() string
@@ -15,6 +18,8 @@ type Hello struct {
 }
 
 // Ready represents the ready event dispatched when a client has completed the initial handshake with the gateway.
+//
+//godiscord:event READY
 type Ready struct {
 	APIVersion       int         `json:"v"`                  // API version.
 	User             User        `json:"user"`               // Information about the user including email.
@@ -25,7 +30,22 @@ type Ready struct {
 	Application      Application `json:"application"`        // Contains id and flags.
 }
 
+func (m Ready) guild() string {
+	return ""
+}
+
+// Resumed is received when a resume has succeeded, once all the missed events have been replayed.
+//
+//godiscord:event RESUMED
+type Resumed struct{}
+
+func (m Resumed) guild() string {
+	return ""
+}
+
 // ApplicationCommandPermissionsUpdate represents the permissions for an application's command(s) in a guild.
+//
+//godiscord:event APPLICATION_COMMAND_PERMISSIONS_UPDATE
 type ApplicationCommandPermissionsUpdate struct {
 	ID            string                          `json:"id"`             // ID of the command or the application ID.
 	ApplicationID string                          `json:"application_id"` // ID of the application the command belongs to.
@@ -37,19 +57,9 @@ func (m ApplicationCommandPermissionsUpdate) guild() string {
 	return m.GuildID
 }
 
-type applicationCommandPermissionsUpdateHandler struct {
-	f func(*Fetcher, ApplicationCommandPermissionsUpdate) error
-}
-
-func (e applicationCommandPermissionsUpdateHandler) name() string {
-	return "APPLICATION_COMMAND_PERMISSIONS_UPDATE"
-}
-
-func (e applicationCommandPermissionsUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(ApplicationCommandPermissionsUpdate))
-}
-
 // AutoModerationRuleCreate is received when a rule is created.
+//
+//godiscord:event AUTO_MODERATION_RULE_CREATE
 type AutoModerationRuleCreate struct {
 	AutoModerationRule
 }
@@ -58,19 +68,9 @@ func (m AutoModerationRuleCreate) guild() string {
 	return m.GuildID
 }
 
-type autoModerationRuleCreateHandler struct {
-	f func(*Fetcher, AutoModerationRuleCreate) error
-}
-
-func (e autoModerationRuleCreateHandler) name() string {
-	return "AUTO_MODERATION_RULE_CREATE"
-}
-
-func (e autoModerationRuleCreateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(AutoModerationRuleCreate))
-}
-
 // AutoModerationRuleUpdate is received when a rule is updated.
+//
+//godiscord:event AUTO_MODERATION_RULE_UPDATE
 type AutoModerationRuleUpdate struct {
 	AutoModerationRule
 }
@@ -79,19 +79,9 @@ func (m AutoModerationRuleUpdate) guild() string {
 	return m.GuildID
 }
 
-type autoModerationRuleUpdateHandler struct {
-	f func(*Fetcher, AutoModerationRuleUpdate) error
-}
-
-func (e autoModerationRuleUpdateHandler) name() string {
-	return "AUTO_MODERATION_RULE_UPDATE"
-}
-
-func (e autoModerationRuleUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(AutoModerationRuleUpdate))
-}
-
 // AutoModerationRuleDelete is received when a rule is deleted.
+//
+//godiscord:event AUTO_MODERATION_RULE_DELETE
 type AutoModerationRuleDelete struct {
 	AutoModerationRule
 }
@@ -100,19 +90,35 @@ func (m AutoModerationRuleDelete) guild() string {
 	return m.GuildID
 }
 
-type autoModerationRuleDeleteHandler struct {
-	f func(*Fetcher, AutoModerationRuleDelete) error
+// AutoModerationActionExecution is received when a rule is triggered and an action is executed.
+//
+//godiscord:event AUTO_MODERATION_ACTION_EXECUTION
+type AutoModerationActionExecution struct {
+	GuildID              string                        `json:"guild_id"`                          // ID of the guild in which action was executed.
+	Action               AutoModerationAction          `json:"action"`                            // Action which was executed.
+	RuleID               string                        `json:"rule_id"`                           // ID of the rule which action belongs to.
+	RuleTriggerType      AutoModerationRuleTriggerType `json:"rule_trigger_type"`                 // Trigger type of rule which was triggered.
+	UserID               string                        `json:"user_id"`                           // ID of the user which generated the content which triggered the rule.
+	ChannelID            *string                       `json:"channel_id,omitempty"`              // ID of the channel in which user content was posted.
+	MessageID            *string                       `json:"message_id,omitempty"`              // ID of any user message which content belongs to.
+	AlertSystemMessageID *string                       `json:"alert_system_message_id,omitempty"` // ID of any system auto moderation messages posted as a result of this action.
+	Content              string                        `json:"content"`                           // User-generated text content.
+	MatchedKeyword       *string                       `json:"matched_keyword,omitempty"`         // Word or phrase configured in the rule that triggered the rule.
+	MatchedContent       *string                       `json:"matched_content,omitempty"`         // Substring in content that triggered the rule.
 }
 
-func (e autoModerationRuleDeleteHandler) name() string {
-	return "AUTO_MODERATION_RULE_DELETE"
+func (m AutoModerationActionExecution) guild() string {
+	return m.GuildID
 }
 
-func (e autoModerationRuleDeleteHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(AutoModerationRuleDelete))
-}
+// AutoModerationActionExecutionEvent is the former name of AutoModerationActionExecution.
+//
+// Deprecated: use AutoModerationActionExecution.
+type AutoModerationActionExecutionEvent = AutoModerationActionExecution
 
 // ChannelCreate is received when a channel is created.
+//
+//godiscord:event CHANNEL_CREATE
 type ChannelCreate struct {
 	Channel
 }
@@ -125,19 +131,9 @@ func (m ChannelCreate) guild() string {
 	return *m.GuildID
 }
 
-type channelCreateHandler struct {
-	f func(*Fetcher, ChannelCreate) error
-}
-
-func (e channelCreateHandler) name() string {
-	return "CHANNEL_CREATE"
-}
-
-func (e channelCreateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(ChannelCreate))
-}
-
 // ChannelUpdate is received when a channel is updated.
+//
+//godiscord:event CHANNEL_UPDATE
 type ChannelUpdate struct {
 	Channel
 }
@@ -150,19 +146,9 @@ func (m ChannelUpdate) guild() string {
 	return *m.GuildID
 }
 
-type channelUpdateHandler struct {
-	f func(*Fetcher, ChannelUpdate) error
-}
-
-func (e channelUpdateHandler) name() string {
-	return "CHANNEL_UPDATE"
-}
-
-func (e channelUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(ChannelUpdate))
-}
-
 // ChannelDelete is received when a channel is deleted.
+//
+//godiscord:event CHANNEL_DELETE
 type ChannelDelete struct {
 	Channel
 }
@@ -175,20 +161,10 @@ func (m ChannelDelete) guild() string {
 	return *m.GuildID
 }
 
-type channelDeleteHandler struct {
-	f func(*Fetcher, ChannelDelete) error
-}
-
-func (e channelDeleteHandler) name() string {
-	return "CHANNEL_DELETE"
-}
-
-func (e channelDeleteHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(ChannelDelete))
-}
-
 // ChannelPinsUpdate is sent when a message is pinned or unpinned in a text channel.
 // This is not sent when a pinned message is deleted.
+//
+//godiscord:event CHANNEL_PINS_UPDATE
 type ChannelPinsUpdate struct {
 	GuildID          string     `json:"guild_id,omitempty"`           // ID of the guild.
 	ChannelID        string     `json:"channel_id"`                   // ID of the channel.
@@ -199,19 +175,9 @@ func (m ChannelPinsUpdate) guild() string {
 	return m.GuildID
 }
 
-type channelPinsUpdateHandler struct {
-	f func(*Fetcher, ChannelPinsUpdate) error
-}
-
-func (e channelPinsUpdateHandler) name() string {
-	return "CHANNEL_PINS_UPDATE"
-}
-
-func (e channelPinsUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(ChannelPinsUpdate))
-}
-
 // ThreadCreate is received when a thread is created.
+//
+//godiscord:event THREAD_CREATE
 type ThreadCreate struct {
 	Channel
 }
@@ -224,19 +190,9 @@ func (m ThreadCreate) guild() string {
 	return *m.Channel.GuildID
 }
 
-type threadCreateHandler struct {
-	f func(*Fetcher, ThreadCreate) error
-}
-
-func (e threadCreateHandler) name() string {
-	return "THREAD_CREATE"
-}
-
-func (e threadCreateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(ThreadCreate))
-}
-
 // ThreadUpdate is received when a thread is updated.
+//
+//godiscord:event THREAD_UPDATE
 type ThreadUpdate struct {
 	Channel
 }
@@ -249,19 +205,9 @@ func (m ThreadUpdate) guild() string {
 	return *m.Channel.GuildID
 }
 
-type threadUpdateHandler struct {
-	f func(*Fetcher, ThreadUpdate) error
-}
-
-func (e threadUpdateHandler) name() string {
-	return "THREAD_UPDATE"
-}
-
-func (e threadUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(ThreadUpdate))
-}
-
 // ThreadDelete is received when a thread is deleted.
+//
+//godiscord:event THREAD_DELETE
 type ThreadDelete struct {
 	Channel
 }
@@ -274,19 +220,9 @@ func (m ThreadDelete) guild() string {
 	return *m.Channel.GuildID
 }
 
-type threadDeleteHandler struct {
-	f func(*Fetcher, ThreadDelete) error
-}
-
-func (e threadDeleteHandler) name() string {
-	return "THREAD_DELETE"
-}
-
-func (e threadDeleteHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(ThreadDelete))
-}
-
 // ThreadListSync is received when gaining access to a channel, contains all active threads in that channel.
+//
+//godiscord:event THREAD_LIST_SYNC
 type ThreadListSync struct {
 	GuildID    string         `json:"guild_id"`              // ID of the guild.
 	ChannelIDs []string       `json:"channel_ids,omitempty"` // Parent channel IDs whose threads are being synced.
@@ -298,19 +234,9 @@ func (m ThreadListSync) guild() string {
 	return m.GuildID
 }
 
-type threadListSyncHandler struct {
-	f func(*Fetcher, ThreadListSync) error
-}
-
-func (e threadListSyncHandler) name() string {
-	return "THREAD_LIST_SYNC"
-}
-
-func (e threadListSyncHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(ThreadListSync))
-}
-
 // ThreadMemberUpdate is received when thread member for the current user is updated.
+//
+//godiscord:event THREAD_MEMBER_UPDATE
 type ThreadMemberUpdate struct {
 	ThreadMember
 
@@ -321,19 +247,9 @@ func (m ThreadMemberUpdate) guild() string {
 	return m.GuildID
 }
 
-type threadMemberUpdateHandler struct {
-	f func(*Fetcher, ThreadMemberUpdate) error
-}
-
-func (e threadMemberUpdateHandler) name() string {
-	return "THREAD_MEMBER_UPDATE"
-}
-
-func (e threadMemberUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(ThreadMemberUpdate))
-}
-
 // ThreadMembersUpdate is received when some user(s) were added to or removed from a thread.
+//
+//godiscord:event THREAD_MEMBERS_UPDATE
 type ThreadMembersUpdate struct {
 	ID               string         `json:"id"`                           // ID of the thread.
 	GuildID          string         `json:"guild_id"`                     // ID of the guild.
@@ -346,19 +262,9 @@ func (m ThreadMembersUpdate) guild() string {
 	return m.GuildID
 }
 
-type threadMembersUpdateHandler struct {
-	f func(*Fetcher, ThreadMembersUpdate) error
-}
-
-func (e threadMembersUpdateHandler) name() string {
-	return "THREAD_MEMBERS_UPDATE"
-}
-
-func (e threadMembersUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(ThreadMembersUpdate))
-}
-
 // EntitlementCreate is received when an entitlement is created.
+//
+//godiscord:event ENTITLEMENT_CREATE
 type EntitlementCreate struct {
 	Entitlement
 }
@@ -371,19 +277,9 @@ func (m EntitlementCreate) guild() string {
 	return *m.Entitlement.GuildID
 }
 
-type entitlementCreateHandler struct {
-	f func(*Fetcher, EntitlementCreate) error
-}
-
-func (e entitlementCreateHandler) name() string {
-	return "ENTITLEMENT_CREATE"
-}
-
-func (e entitlementCreateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(EntitlementCreate))
-}
-
 // EntitlementUpdate is received when an entitlement is updated.
+//
+//godiscord:event ENTITLEMENT_UPDATE
 type EntitlementUpdate struct {
 	Entitlement
 }
@@ -396,19 +292,9 @@ func (m EntitlementUpdate) guild() string {
 	return *m.Entitlement.GuildID
 }
 
-type entitlementUpdateHandler struct {
-	f func(*Fetcher, EntitlementUpdate) error
-}
-
-func (e entitlementUpdateHandler) name() string {
-	return "ENTITLEMENT_UPDATE"
-}
-
-func (e entitlementUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(EntitlementUpdate))
-}
-
 // EntitlementDelete is received when an entitlement is deleted.
+//
+//godiscord:event ENTITLEMENT_DELETE
 type EntitlementDelete struct {
 	Entitlement
 }
@@ -421,22 +307,12 @@ func (m EntitlementDelete) guild() string {
 	return *m.Entitlement.GuildID
 }
 
-type entitlementDeleteHandler struct {
-	f func(*Fetcher, EntitlementDelete) error
-}
-
-func (e entitlementDeleteHandler) name() string {
-	return "ENTITLEMENT_DELETE"
-}
-
-func (e entitlementDeleteHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(EntitlementDelete))
-}
-
 // GuildCreate represents the event sent when a user initially connects or when a guild becomes available again to the client, or when the current user joins a new guild.
 // If your bot does not have the GUILD_PRESENCES Gateway Intent,
 // or if the guild has over 75k members,
 // members and presences returned in this event will only contain your bot and users in voice channels.
+//
+//godiscord:event GUILD_CREATE
 type GuildCreate struct {
 	Guild
 	JoinedAt             time.Time             `json:"joined_at"`              // When this guild was joined at.
@@ -447,70 +323,55 @@ type GuildCreate struct {
 	Members              []GuildMember         `json:"members"`                // Users in the guild.
 	Channels             []Channel             `json:"channels"`               // Channels in the guild.
 	Threads              []Channel             `json:"threads"`                // All active threads in the guild that current user has permission to view.
-	Presences            []Presence            `json:"presences"`              // Presences of the members in the guild, will only include non-offline members if the size is greater than large threshold.
+	Presences            []PresenceUpdate      `json:"presences"`              // Presences of the members in the guild, will only include non-offline members if the size is greater than large threshold.
 	StageInstances       []StageInstance       `json:"stage_instances"`        // Stage instances in the guild.
 	GuildScheduledEvents []GuildScheduledEvent `json:"guild_scheduled_events"` // Scheduled events in the guild.
+	SoundboardSounds     []SoundboardSound     `json:"soundboard_sounds"`      // Soundboard sounds in the guild.
 }
 
 func (m GuildCreate) guild() string {
 	return m.Guild.ID
 }
 
-type guildCreateHandler struct {
-	f func(*Fetcher, GuildCreate) error
-}
-
-func (e guildCreateHandler) name() string {
-	return "GUILD_CREATE"
-}
-
-func (e guildCreateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildCreate))
-}
-
 // GuildUpdate is received when a guild is updated
+//
+//godiscord:event GUILD_UPDATE
 type GuildUpdate struct {
-	Guild Guild `json:"guild"`
+	Guild
 }
 
 func (m GuildUpdate) guild() string {
 	return m.Guild.ID
 }
 
-type guildUpdateHandler struct {
-	f func(*Fetcher, GuildUpdate) error
-}
-
-func (e guildUpdateHandler) name() string {
-	return "GUILD_UPDATE"
-}
-
-func (e guildUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildUpdate))
-}
-
-// GuildDelete is received when a guild is deleted.
+// GuildDelete is received when the bot leaves a guild, or the guild becomes unavailable, in which case only its ID
+// and Unavailable are set.
+//
+//godiscord:event GUILD_DELETE
 type GuildDelete struct {
-	Guild Guild `json:"guild"`
+	Guild
 }
 
 func (m GuildDelete) guild() string {
 	return m.Guild.ID
 }
 
-type guildDeleteHandler struct {
-	f func(*Fetcher, GuildDelete) error
-}
+// GuildAuditLogEntryCreate is received when an entry is added to the audit log of a guild.
+//
+//godiscord:event GUILD_AUDIT_LOG_ENTRY_CREATE
+type GuildAuditLogEntryCreate struct {
+	AuditLogEntry
 
-func (e guildDeleteHandler) name() string {
-	return "GUILD_DELETE"
+	GuildID string `json:"guild_id"` // ID of the guild.
 }
 
-func (e guildDeleteHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildDelete))
+func (m GuildAuditLogEntryCreate) guild() string {
+	return m.GuildID
 }
 
 // GuildBanAdd is received when a user was banned from a guild.
+//
+//godiscord:event GUILD_BAN_ADD
 type GuildBanAdd struct {
 	GuildID string `json:"guild_id"` // ID of the guild.
 	User    User   `json:"user"`     // User who was banned.
@@ -520,19 +381,9 @@ func (m GuildBanAdd) guild() string {
 	return m.GuildID
 }
 
-type guildBanAddHandler struct {
-	f func(*Fetcher, GuildBanAdd) error
-}
-
-func (e guildBanAddHandler) name() string {
-	return "GUILD_BAN_ADD"
-}
-
-func (e guildBanAddHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildBanAdd))
-}
-
 // GuildBanRemove is received when a user was unbanned from a guild.
+//
+//godiscord:event GUILD_BAN_REMOVE
 type GuildBanRemove struct {
 	GuildID string `json:"guild_id"` // ID of the guild.
 	User    User   `json:"user"`     // User who was unbanned.
@@ -542,19 +393,9 @@ func (m GuildBanRemove) guild() string {
 	return m.GuildID
 }
 
-type guildBanRemoveHandler struct {
-	f func(*Fetcher, GuildBanRemove) error
-}
-
-func (e guildBanRemoveHandler) name() string {
-	return "GUILD_BAN_REMOVE"
-}
-
-func (e guildBanRemoveHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildBanRemove))
-}
-
 // GuildEmojisUpdate is received when guild emojis were updated
+//
+//godiscord:event GUILD_EMOJIS_UPDATE
 type GuildEmojisUpdate struct {
 	GuildID string  `json:"guild_id"` // ID of the guild.
 	Emojis  []Emoji `json:"emojis"`   // Array of emojis.
@@ -564,19 +405,9 @@ func (m GuildEmojisUpdate) guild() string {
 	return m.GuildID
 }
 
-type guildEmojisUpdateHandler struct {
-	f func(*Fetcher, GuildEmojisUpdate) error
-}
-
-func (e guildEmojisUpdateHandler) name() string {
-	return "GUILD_EMOJIS_UPDATE"
-}
-
-func (e guildEmojisUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildEmojisUpdate))
-}
-
 // GuildStickersUpdate is received when guild stickers were updated.
+//
+//godiscord:event GUILD_STICKERS_UPDATE
 type GuildStickersUpdate struct {
 	GuildID  string    `json:"guild_id"` // ID of the guild.
 	Stickers []Sticker `json:"stickers"` // Array of stickers.
@@ -586,19 +417,20 @@ func (m GuildStickersUpdate) guild() string {
 	return m.GuildID
 }
 
-type guildStickersUpdateHandler struct {
-	f func(*Fetcher, GuildStickersUpdate) error
-}
-
-func (e guildStickersUpdateHandler) name() string {
-	return "GUILD_STICKERS_UPDATE"
+// GuildIntegrationsUpdate is received when the integrations of a guild were updated.
+//
+//godiscord:event GUILD_INTEGRATIONS_UPDATE
+type GuildIntegrationsUpdate struct {
+	GuildID string `json:"guild_id"` // ID of the guild whose integrations were updated.
 }
 
-func (e guildStickersUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildStickersUpdate))
+func (m GuildIntegrationsUpdate) guild() string {
+	return m.GuildID
 }
 
 // GuildMemberAdd is received when a user joins a guild.
+//
+//godiscord:event GUILD_MEMBER_ADD
 type GuildMemberAdd struct {
 	GuildMember
 
@@ -609,19 +441,9 @@ func (m GuildMemberAdd) guild() string {
 	return m.GuildID
 }
 
-type guildMemberAddHandler struct {
-	f func(*Fetcher, GuildMemberAdd) error
-}
-
-func (e guildMemberAddHandler) name() string {
-	return "GUILD_MEMBER_ADD"
-}
-
-func (e guildMemberAddHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildMemberAdd))
-}
-
 // GuildMemberUpdate is received when a guild member was updated.
+//
+//godiscord:event GUILD_MEMBER_UPDATE
 type GuildMemberUpdate struct {
 	GuildID                    string     `json:"guild_id"`                     // ID of the guild.
 	Roles                      []string   `json:"roles"`                        // User role ids.
@@ -640,19 +462,9 @@ func (m GuildMemberUpdate) guild() string {
 	return m.GuildID
 }
 
-type guildMemberUpdateHandler struct {
-	f func(*Fetcher, GuildMemberUpdate) error
-}
-
-func (e guildMemberUpdateHandler) name() string {
-	return "GUILD_MEMBER_UPDATE"
-}
-
-func (e guildMemberUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildMemberUpdate))
-}
-
 // GuildMemberRemove is received when a user was removed from the guild.
+//
+//godiscord:event GUILD_MEMBER_REMOVE
 type GuildMemberRemove struct {
 	GuildID string `json:"guild_id"` // ID of the guild.
 	User    User   `json:"user"`     // User who was removed.
@@ -662,19 +474,9 @@ func (m GuildMemberRemove) guild() string {
 	return m.GuildID
 }
 
-type guildMemberRemoveHandler struct {
-	f func(*Fetcher, GuildMemberRemove) error
-}
-
-func (e guildMemberRemoveHandler) name() string {
-	return "GUILD_MEMBER_REMOVE"
-}
-
-func (e guildMemberRemoveHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildMemberRemove))
-}
-
 // GuildMembersChunk is received in response to Guild Request Members. You can use the chunk_index and chunk_count to calculate how many chunks are left for your request.
+//
+//godiscord:event GUILD_MEMBERS_CHUNK
 type GuildMembersChunk struct {
 	GuildID    string        `json:"guild_id"`            // ID of the guild.
 	Members    []GuildMember `json:"members"`             // Set of guild members.
@@ -689,19 +491,9 @@ func (m GuildMembersChunk) guild() string {
 	return m.GuildID
 }
 
-type guildMembersChunkHandler struct {
-	f func(*Fetcher, GuildMembersChunk) error
-}
-
-func (e guildMembersChunkHandler) name() string {
-	return "GUILD_MEMBERS_CHUNK"
-}
-
-func (e guildMembersChunkHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildMembersChunk))
-}
-
 // GuildRoleCreate is received when a new role was created.
+//
+//godiscord:event GUILD_ROLE_CREATE
 type GuildRoleCreate struct {
 	GuildID string `json:"guild_id"` // ID of the guild.
 	Role    Role   `json:"role"`     // Role that was created.
@@ -711,19 +503,9 @@ func (m GuildRoleCreate) guild() string {
 	return m.GuildID
 }
 
-type guildRoleCreateHandler struct {
-	f func(*Fetcher, GuildRoleCreate) error
-}
-
-func (e guildRoleCreateHandler) name() string {
-	return "GUILD_ROLE_CREATE"
-}
-
-func (e guildRoleCreateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildRoleCreate))
-}
-
 // GuildRoleUpdate is received when a role was updated.
+//
+//godiscord:event GUILD_ROLE_UPDATE
 type GuildRoleUpdate struct {
 	GuildID string `json:"guild_id"` // ID of the guild.
 	Role    Role   `json:"role"`     // Role that was updated.
@@ -733,19 +515,9 @@ func (m GuildRoleUpdate) guild() string {
 	return m.GuildID
 }
 
-type guildRoleUpdateHandler struct {
-	f func(*Fetcher, GuildRoleUpdate) error
-}
-
-func (e guildRoleUpdateHandler) name() string {
-	return "GUILD_ROLE_UPDATE"
-}
-
-func (e guildRoleUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildRoleUpdate))
-}
-
 // GuildRoleDelete is received when a role was deleted.
+//
+//godiscord:event GUILD_ROLE_DELETE
 type GuildRoleDelete struct {
 	GuildID string `json:"guild_id"` // ID of the guild.
 	RoleID  string `json:"role_id"`  // ID of the role.
@@ -755,19 +527,9 @@ func (m GuildRoleDelete) guild() string {
 	return m.GuildID
 }
 
-type guildRoleDeleteHandler struct {
-	f func(*Fetcher, GuildRoleDelete) error
-}
-
-func (e guildRoleDeleteHandler) name() string {
-	return "GUILD_ROLE_DELETE"
-}
-
-func (e guildRoleDeleteHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildRoleDelete))
-}
-
 // GuildScheduledEventCreate is received when a new scheduled event is created.
+//
+//godiscord:event GUILD_SCHEDULED_EVENT_CREATE
 type GuildScheduledEventCreate struct {
 	GuildScheduledEvent
 }
@@ -776,19 +538,9 @@ func (m GuildScheduledEventCreate) guild() string {
 	return m.GuildID
 }
 
-type guildScheduledEventCreateHandler struct {
-	f func(*Fetcher, GuildScheduledEventCreate) error
-}
-
-func (e guildScheduledEventCreateHandler) name() string {
-	return "GUILD_SCHEDULED_EVENT_CREATE"
-}
-
-func (e guildScheduledEventCreateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildScheduledEventCreate))
-}
-
 // GuildScheduledEventUpdate is received when a new scheduled event is updated.
+//
+//godiscord:event GUILD_SCHEDULED_EVENT_UPDATE
 type GuildScheduledEventUpdate struct {
 	GuildScheduledEvent
 }
@@ -797,19 +549,9 @@ func (m GuildScheduledEventUpdate) guild() string {
 	return m.GuildID
 }
 
-type guildScheduledEventUpdateHandler struct {
-	f func(*Fetcher, GuildScheduledEventUpdate) error
-}
-
-func (e guildScheduledEventUpdateHandler) name() string {
-	return "GUILD_SCHEDULED_EVENT_UPDATE"
-}
-
-func (e guildScheduledEventUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildScheduledEventUpdate))
-}
-
 // GuildScheduledEventDelete is received when a new scheduled event is deleted.
+//
+//godiscord:event GUILD_SCHEDULED_EVENT_DELETE
 type GuildScheduledEventDelete struct {
 	GuildScheduledEvent
 }
@@ -818,65 +560,103 @@ func (m GuildScheduledEventDelete) guild() string {
 	return m.GuildID
 }
 
-type guildScheduledEventDeleteHandler struct {
-	f func(*Fetcher, GuildScheduledEventDelete) error
+// GuildScheduledEventUserAdd is received when a user has subscribed to a guild scheduled event.
+//
+//godiscord:event GUILD_SCHEDULED_EVENT_USER_ADD
+type GuildScheduledEventUserAdd struct {
+	GuildScheduledEventID string `json:"guild_scheduled_event_id"`
+	UserID                string `json:"user_id"`
+	GuildID               string `json:"guild_id"`
 }
 
-func (e guildScheduledEventDeleteHandler) name() string {
-	return "GUILD_SCHEDULED_EVENT_DELETE"
+func (m GuildScheduledEventUserAdd) guild() string {
+	return m.GuildID
 }
 
-func (e guildScheduledEventDeleteHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildScheduledEventDelete))
-}
+// GuildScheduledEventUserAddEvent is the former name of GuildScheduledEventUserAdd.
+//
+// Deprecated: use GuildScheduledEventUserAdd.
+type GuildScheduledEventUserAddEvent = GuildScheduledEventUserAdd
 
-// GuildScheduledEventUserAddEvent is received when a user has subscribed to a guild scheduled event.
-type GuildScheduledEventUserAddEvent struct {
+// GuildScheduledEventUserRemove is received when a user has unsubscribed from a guild scheduled event.
+//
+//godiscord:event GUILD_SCHEDULED_EVENT_USER_REMOVE
+type GuildScheduledEventUserRemove struct {
 	GuildScheduledEventID string `json:"guild_scheduled_event_id"`
 	UserID                string `json:"user_id"`
 	GuildID               string `json:"guild_id"`
 }
 
-func (m GuildScheduledEventUserAddEvent) guild() string {
+func (m GuildScheduledEventUserRemove) guild() string {
 	return m.GuildID
 }
 
-type guildScheduledEventUserAddEventHandler struct {
-	f func(*Fetcher, GuildScheduledEventUserAddEvent) error
+// GuildScheduledEventUserRemoveEvent is the former name of GuildScheduledEventUserRemove.
+//
+// Deprecated: use GuildScheduledEventUserRemove.
+type GuildScheduledEventUserRemoveEvent = GuildScheduledEventUserRemove
+
+// GuildSoundboardSoundCreate is received when a soundboard sound is created.
+//
+//godiscord:event GUILD_SOUNDBOARD_SOUND_CREATE
+type GuildSoundboardSoundCreate struct {
+	SoundboardSound
 }
 
-func (e guildScheduledEventUserAddEventHandler) name() string {
-	return "GUILD_SCHEDULED_EVENT_USER_ADD_EVENT"
+func (m GuildSoundboardSoundCreate) guild() string {
+	return m.GuildID
 }
 
-func (e guildScheduledEventUserAddEventHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildScheduledEventUserAddEvent))
+// GuildSoundboardSoundUpdate is received when a soundboard sound is updated.
+//
+//godiscord:event GUILD_SOUNDBOARD_SOUND_UPDATE
+type GuildSoundboardSoundUpdate struct {
+	SoundboardSound
 }
 
-// GuildScheduledEventUserRemoveEvent is received when a user has unsubscribed from a guild scheduled event.
-type GuildScheduledEventUserRemoveEvent struct {
-	GuildScheduledEventID string `json:"guild_scheduled_event_id"`
-	UserID                string `json:"user_id"`
-	GuildID               string `json:"guild_id"`
+func (m GuildSoundboardSoundUpdate) guild() string {
+	return m.GuildID
+}
+
+// GuildSoundboardSoundDelete is received when a soundboard sound is deleted.
+//
+//godiscord:event GUILD_SOUNDBOARD_SOUND_DELETE
+type GuildSoundboardSoundDelete struct {
+	SoundID string `json:"sound_id"` // ID of the sound that was deleted.
+	GuildID string `json:"guild_id"` // ID of the guild the sound was in.
 }
 
-func (m GuildScheduledEventUserRemoveEvent) guild() string {
+func (m GuildSoundboardSoundDelete) guild() string {
 	return m.GuildID
 }
 
-type guildScheduledEventUserRemoveEventHandler struct {
-	f func(*Fetcher, GuildScheduledEventUserRemoveEvent) error
+// GuildSoundboardSoundsUpdate is received when multiple soundboard sounds are updated at once.
+//
+//godiscord:event GUILD_SOUNDBOARD_SOUNDS_UPDATE
+type GuildSoundboardSoundsUpdate struct {
+	SoundboardSounds []SoundboardSound `json:"soundboard_sounds"` // The updated sounds.
+	GuildID          string            `json:"guild_id"`          // ID of the guild.
 }
 
-func (e guildScheduledEventUserRemoveEventHandler) name() string {
-	return "GUILD_SCHEDULED_EVENT_USER_REMOVE_EVENT"
+func (m GuildSoundboardSoundsUpdate) guild() string {
+	return m.GuildID
 }
 
-func (e guildScheduledEventUserRemoveEventHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(GuildScheduledEventUserRemoveEvent))
+// SoundboardSounds is received in response to a request of the soundboard sounds of a guild.
+//
+//godiscord:event SOUNDBOARD_SOUNDS
+type SoundboardSounds struct {
+	SoundboardSounds []SoundboardSound `json:"soundboard_sounds"` // The sounds of the guild.
+	GuildID          string            `json:"guild_id"`          // ID of the guild.
+}
+
+func (m SoundboardSounds) guild() string {
+	return m.GuildID
 }
 
 // IntegrationCreate is received when a new integration is created.
+//
+//godiscord:event INTEGRATION_CREATE
 type IntegrationCreate struct {
 	Integration
 
@@ -887,19 +667,9 @@ func (m IntegrationCreate) guild() string {
 	return m.GuildID
 }
 
-type integrationCreateHandler struct {
-	f func(*Fetcher, IntegrationCreate) error
-}
-
-func (e integrationCreateHandler) name() string {
-	return "INTEGRATION_CREATE"
-}
-
-func (e integrationCreateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(IntegrationCreate))
-}
-
 // IntegrationUpdate is received when a new integration is updated.
+//
+//godiscord:event INTEGRATION_UPDATE
 type IntegrationUpdate struct {
 	Integration
 
@@ -910,19 +680,9 @@ func (m IntegrationUpdate) guild() string {
 	return m.GuildID
 }
 
-type integrationUpdateHandler struct {
-	f func(*Fetcher, IntegrationUpdate) error
-}
-
-func (e integrationUpdateHandler) name() string {
-	return "INTEGRATION_UPDATE"
-}
-
-func (e integrationUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(IntegrationUpdate))
-}
-
 // IntegrationDelete is sent when an integration is deleted.
+//
+//godiscord:event INTEGRATION_DELETE
 type IntegrationDelete struct {
 	ID            string  `json:"id"`                       // Integration ID.
 	GuildID       string  `json:"guild_id"`                 // ID of the guild.
@@ -933,19 +693,9 @@ func (m IntegrationDelete) guild() string {
 	return m.GuildID
 }
 
-type integrationDeleteHandler struct {
-	f func(*Fetcher, IntegrationDelete) error
-}
-
-func (e integrationDeleteHandler) name() string {
-	return "INTEGRATION_DELETE"
-}
-
-func (e integrationDeleteHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(IntegrationDelete))
-}
-
 // InviteCreate is sent when an invite to a channel is created.
+//
+//godiscord:event INVITE_CREATE
 type InviteCreate struct {
 	ChannelID         string           `json:"channel_id"`                   // Channel the invite is for.
 	Code              string           `json:"code"`                         // Unique invite code.
@@ -965,19 +715,9 @@ func (m InviteCreate) guild() string {
 	return m.GuildID
 }
 
-type inviteCreateHandler struct {
-	f func(*Fetcher, InviteCreate) error
-}
-
-func (e inviteCreateHandler) name() string {
-	return "INVITE_CREATE"
-}
-
-func (e inviteCreateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(InviteCreate))
-}
-
 // InviteDelete is sent when an invite is deleted.
+//
+//godiscord:event INVITE_DELETE
 type InviteDelete struct {
 	ChannelID string `json:"channel_id"`         // Channel of the invite.
 	GuildID   string `json:"guild_id,omitempty"` // Guild of the invite.
@@ -988,19 +728,9 @@ func (m InviteDelete) guild() string {
 	return m.GuildID
 }
 
-type inviteDeleteHandler struct {
-	f func(*Fetcher, InviteDelete) error
-}
-
-func (e inviteDeleteHandler) name() string {
-	return "INVITE_DELETE"
-}
-
-func (e inviteDeleteHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(InviteDelete))
-}
-
 // MessageCreate is sent when a message is created.
+//
+//godiscord:event MESSAGE_CREATE
 type MessageCreate struct {
 	Message
 
@@ -1017,19 +747,9 @@ func (m MessageCreate) guild() string {
 	return m.GuildID
 }
 
-type messageCreateHandler struct {
-	f func(*Fetcher, MessageCreate) error
-}
-
-func (e messageCreateHandler) name() string {
-	return "MESSAGE_CREATE"
-}
-
-func (e messageCreateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(MessageCreate))
-}
-
 // MessageUpdate is sent when a message is created.
+//
+//godiscord:event MESSAGE_UPDATE
 type MessageUpdate struct {
 	Message
 
@@ -1046,19 +766,9 @@ func (m MessageUpdate) guild() string {
 	return m.GuildID
 }
 
-type messageUpdateHandler struct {
-	f func(*Fetcher, MessageUpdate) error
-}
-
-func (e messageUpdateHandler) name() string {
-	return "MESSAGE_UPDATE"
-}
-
-func (e messageUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(MessageUpdate))
-}
-
 // MessageDelete is sent with a message delete event.
+//
+//godiscord:event MESSAGE_DELETE
 type MessageDelete struct {
 	ID        string `json:"id"`                 // ID of the message.
 	ChannelID string `json:"channel_id"`         // ID of the channel.
@@ -1069,19 +779,9 @@ func (m MessageDelete) guild() string {
 	return m.GuildID
 }
 
-type messageDeleteHandler struct {
-	f func(*Fetcher, MessageDelete) error
-}
-
-func (e messageDeleteHandler) name() string {
-	return "MESSAGE_DELETE"
-}
-
-func (e messageDeleteHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(MessageDelete))
-}
-
 // MessageDeleteBulk is sent with a bulk message delete event.
+//
+//godiscord:event MESSAGE_DELETE_BULK
 type MessageDeleteBulk struct {
 	IDs       []string `json:"ids"`                // IDs of the deleted messages.
 	ChannelID string   `json:"channel_id"`         // ID of the channel.
@@ -1092,19 +792,9 @@ func (m MessageDeleteBulk) guild() string {
 	return m.GuildID
 }
 
-type messageDeleteBulkHandler struct {
-	f func(*Fetcher, MessageDeleteBulk) error
-}
-
-func (e messageDeleteBulkHandler) name() string {
-	return "MESSAGE_DELETE_BULK"
-}
-
-func (e messageDeleteBulkHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(MessageDeleteBulk))
-}
-
 // MessageReactionAdd is sent when a user adds a reaction to a message.
+//
+//godiscord:event MESSAGE_REACTION_ADD
 type MessageReactionAdd struct {
 	UserID          string       `json:"user_id"`                     // ID of the user.
 	ChannelID       string       `json:"channel_id"`                  // ID of the channel.
@@ -1119,19 +809,9 @@ func (m MessageReactionAdd) guild() string {
 	return m.GuildID
 }
 
-type messageReactionAddHandler struct {
-	f func(*Fetcher, MessageReactionAdd) error
-}
-
-func (e messageReactionAddHandler) name() string {
-	return "MESSAGE_REACTION_ADD"
-}
-
-func (e messageReactionAddHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(MessageReactionAdd))
-}
-
 // MessageReactionRemove is sent when a user removes a reaction from a message.
+//
+//godiscord:event MESSAGE_REACTION_REMOVE
 type MessageReactionRemove struct {
 	UserID    string `json:"user_id"`            // ID of the user.
 	ChannelID string `json:"channel_id"`         // ID of the channel.
@@ -1144,19 +824,9 @@ func (m MessageReactionRemove) guild() string {
 	return m.GuildID
 }
 
-type messageReactionRemoveHandler struct {
-	f func(*Fetcher, MessageReactionRemove) error
-}
-
-func (e messageReactionRemoveHandler) name() string {
-	return "MESSAGE_REACTION_REMOVE"
-}
-
-func (e messageReactionRemoveHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(MessageReactionRemove))
-}
-
 // MessageReactionRemoveAll is sent when a user explicitly removes all reactions from a message.
+//
+//godiscord:event MESSAGE_REACTION_REMOVE_ALL
 type MessageReactionRemoveAll struct {
 	ChannelID string `json:"channel_id"`         // ID of the channel.
 	MessageID string `json:"message_id"`         // ID of the message.
@@ -1167,19 +837,9 @@ func (m MessageReactionRemoveAll) guild() string {
 	return m.GuildID
 }
 
-type messageReactionRemoveAllHandler struct {
-	f func(*Fetcher, MessageReactionRemoveAll) error
-}
-
-func (e messageReactionRemoveAllHandler) name() string {
-	return "MESSAGE_REACTION_REMOVE_ALL"
-}
-
-func (e messageReactionRemoveAllHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(MessageReactionRemoveAll))
-}
-
 // MessageReactionRemoveEmoji is sent when a user removes a reaction with a specific emoji from a message.
+//
+//godiscord:event MESSAGE_REACTION_REMOVE_EMOJI
 type MessageReactionRemoveEmoji struct {
 	ChannelID string `json:"channel_id"`         // ID of the channel.
 	GuildID   string `json:"guild_id,omitempty"` // ID of the guild (optional).
@@ -1191,19 +851,39 @@ func (m MessageReactionRemoveEmoji) guild() string {
 	return m.GuildID
 }
 
-type messageReactionRemoveEmojiHandler struct {
-	f func(*Fetcher, MessageReactionRemoveEmoji) error
+// MessagePollVoteAdd is sent when a user votes on a poll.
+//
+//godiscord:event MESSAGE_POLL_VOTE_ADD
+type MessagePollVoteAdd struct {
+	UserID    string `json:"user_id"`            // ID of the user.
+	ChannelID string `json:"channel_id"`         // ID of the channel.
+	MessageID string `json:"message_id"`         // ID of the message.
+	GuildID   string `json:"guild_id,omitempty"` // ID of the guild (optional).
+	AnswerID  int    `json:"answer_id"`          // ID of the answer.
 }
 
-func (e messageReactionRemoveEmojiHandler) name() string {
-	return "MESSAGE_REACTION_REMOVE_EMOJI"
+func (m MessagePollVoteAdd) guild() string {
+	return m.GuildID
+}
+
+// MessagePollVoteRemove is sent when a user removes their vote on a poll.
+//
+//godiscord:event MESSAGE_POLL_VOTE_REMOVE
+type MessagePollVoteRemove struct {
+	UserID    string `json:"user_id"`            // ID of the user.
+	ChannelID string `json:"channel_id"`         // ID of the channel.
+	MessageID string `json:"message_id"`         // ID of the message.
+	GuildID   string `json:"guild_id,omitempty"` // ID of the guild (optional).
+	AnswerID  int    `json:"answer_id"`          // ID of the answer.
 }
 
-func (e messageReactionRemoveEmojiHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(MessageReactionRemoveEmoji))
+func (m MessagePollVoteRemove) guild() string {
+	return m.GuildID
 }
 
 // PresenceUpdate is sent when a user's presence in a guild is updated.
+//
+//godiscord:event PRESENCE_UPDATE
 type PresenceUpdate struct {
 	User         User         `json:"user"`          // User whose presence is being updated.
 	GuildID      string       `json:"guild_id"`      // ID of the guild.
@@ -1216,19 +896,24 @@ func (m PresenceUpdate) guild() string {
 	return m.GuildID
 }
 
-type presenceUpdateHandler struct {
-	f func(*Fetcher, PresenceUpdate) error
+// InteractionCreate is sent when a user uses an application command or a message component.
+//
+//godiscord:event INTERACTION_CREATE
+type InteractionCreate struct {
+	Interaction
 }
 
-func (e presenceUpdateHandler) name() string {
-	return "PRESENCE_UPDATE"
-}
+func (m InteractionCreate) guild() string {
+	if m.GuildID == nil {
+		return ""
+	}
 
-func (e presenceUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(PresenceUpdate))
+	return *m.GuildID
 }
 
 // StageInstanceCreate is received when a new stage instance is created.
+//
+//godiscord:event STAGE_INSTANCE_CREATE
 type StageInstanceCreate struct {
 	StageInstance
 }
@@ -1237,19 +922,9 @@ func (m StageInstanceCreate) guild() string {
 	return m.GuildID
 }
 
-type stageInstanceCreateHandler struct {
-	f func(*Fetcher, StageInstanceCreate) error
-}
-
-func (e stageInstanceCreateHandler) name() string {
-	return "STAGE_INSTANCE_CREATE"
-}
-
-func (e stageInstanceCreateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(StageInstanceCreate))
-}
-
 // StageInstanceCreate is received when a new stage instance is updated.
+//
+//godiscord:event STAGE_INSTANCE_UPDATE
 type StageInstanceUpdate struct {
 	StageInstance
 }
@@ -1258,19 +933,9 @@ func (m StageInstanceUpdate) guild() string {
 	return m.GuildID
 }
 
-type stageInstanceUpdateHandler struct {
-	f func(*Fetcher, StageInstanceUpdate) error
-}
-
-func (e stageInstanceUpdateHandler) name() string {
-	return "STAGE_INSTANCE_UPDATE"
-}
-
-func (e stageInstanceUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(StageInstanceUpdate))
-}
-
 // StageInstanceDelete is received when an stage instance is deleted.
+//
+//godiscord:event STAGE_INSTANCE_DELETE
 type StageInstanceDelete struct {
 	StageInstance
 }
@@ -1279,19 +944,9 @@ func (m StageInstanceDelete) guild() string {
 	return m.GuildID
 }
 
-type stageInstanceDeleteHandler struct {
-	f func(*Fetcher, StageInstanceDelete) error
-}
-
-func (e stageInstanceDeleteHandler) name() string {
-	return "STAGE_INSTANCE_DELETE"
-}
-
-func (e stageInstanceDeleteHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(StageInstanceDelete))
-}
-
 // TypingStart is received when a user starts typing in a channel.
+//
+//godiscord:event TYPING_START
 type TypingStart struct {
 	ChannelID string       `json:"channel_id"`         // ID of the channel.
 	GuildID   *string      `json:"guild_id,omitempty"` // ID of the guild.
@@ -1308,19 +963,9 @@ func (m TypingStart) guild() string {
 	return *m.GuildID
 }
 
-type typingStartHandler struct {
-	f func(*Fetcher, TypingStart) error
-}
-
-func (e typingStartHandler) name() string {
-	return "TYPING_START"
-}
-
-func (e typingStartHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(TypingStart))
-}
-
 // UserUpdate is received when a user is updated.
+//
+//godiscord:event USER_UPDATE
 type UserUpdate struct {
 	User
 }
@@ -1329,19 +974,9 @@ func (m UserUpdate) guild() string {
 	return ""
 }
 
-type userUpdateHandler struct {
-	f func(*Fetcher, UserUpdate) error
-}
-
-func (e userUpdateHandler) name() string {
-	return "USER_UPDATE"
-}
-
-func (e userUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(UserUpdate))
-}
-
 // VoiceStateUpdate is received when someone joins/leaves/moves voice channels.
+//
+//godiscord:event VOICE_STATE_UPDATE
 type VoiceStateUpdate struct {
 	VoiceState
 }
@@ -1354,20 +989,10 @@ func (m VoiceStateUpdate) guild() string {
 	return *m.VoiceState.GuildID
 }
 
-type voiceStateUpdateHandler struct {
-	f func(*Fetcher, VoiceStateUpdate) error
-}
-
-func (e voiceStateUpdateHandler) name() string {
-	return "VOICE_STATE_UPDATE"
-}
-
-func (e voiceStateUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(VoiceStateUpdate))
-}
-
 // VoiceServerUpdate is received when a guild's voice server is updated. This is sent when initially connecting to voice, and when the current voice instance fails over to a new server.
 // A null endpoint means that the voice server allocated has gone away and is trying to be reallocated. You should attempt to disconnect from the currently connected voice server, and not attempt to reconnect until a new voice server is allocated.
+//
+//godiscord:event VOICE_SERVER_UPDATE
 type VoiceServerUpdate struct {
 	Token    string  `json:"token"`              // Voice connection token.
 	GuildID  string  `json:"guild_id"`           // Guild this voice server update is for.
@@ -1378,19 +1003,9 @@ func (m VoiceServerUpdate) guild() string {
 	return m.GuildID
 }
 
-type voiceServerUpdateHandler struct {
-	f func(*Fetcher, VoiceServerUpdate) error
-}
-
-func (e voiceServerUpdateHandler) name() string {
-	return "VOICE_SERVER_UPDATE"
-}
-
-func (e voiceServerUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(VoiceServerUpdate))
-}
-
 // WebhooksUpdate is received when a guild channel's webhook is created, updated, or deleted.
+//
+//godiscord:event WEBHOOKS_UPDATE
 type WebhooksUpdate struct {
 	GuildID   string `json:"guild_id"`   // ID of the guild.
 	ChannelID string `json:"channel_id"` // ID of the channel.
@@ -1399,200 +1014,3 @@ type WebhooksUpdate struct {
 func (m WebhooksUpdate) guild() string {
 	return m.GuildID
 }
-
-type webhooksUpdateHandler struct {
-	f func(*Fetcher, WebhooksUpdate) error
-}
-
-func (e webhooksUpdateHandler) name() string {
-	return "WEBHOOKS_UPDATE"
-}
-
-func (e webhooksUpdateHandler) run(fetcher *Fetcher, ev any) error {
-	return e.f(fetcher, ev.(WebhooksUpdate))
-}
-
-type eventHandler interface {
-	run(*Fetcher, any) error
-	name() string
-}
-
-func eventHandlerFromInterface(iface any) (eventHandler, error) {
-	switch v := iface.(type) {
-	case func(*Fetcher, ApplicationCommandPermissionsUpdate) error:
-		return applicationCommandPermissionsUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, AutoModerationRuleCreate) error:
-		return autoModerationRuleCreateHandler{f: v}, nil
-
-	case func(*Fetcher, AutoModerationRuleUpdate) error:
-		return autoModerationRuleUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, AutoModerationRuleDelete) error:
-		return autoModerationRuleDeleteHandler{f: v}, nil
-
-	case func(*Fetcher, ChannelCreate) error:
-		return channelCreateHandler{f: v}, nil
-
-	case func(*Fetcher, ChannelUpdate) error:
-		return channelUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, ChannelDelete) error:
-		return channelDeleteHandler{f: v}, nil
-
-	case func(*Fetcher, ChannelPinsUpdate) error:
-		return channelPinsUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, ThreadCreate) error:
-		return threadCreateHandler{f: v}, nil
-
-	case func(*Fetcher, ThreadUpdate) error:
-		return threadUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, ThreadDelete) error:
-		return threadDeleteHandler{f: v}, nil
-
-	case func(*Fetcher, ThreadListSync) error:
-		return threadListSyncHandler{f: v}, nil
-
-	case func(*Fetcher, ThreadMemberUpdate) error:
-		return threadMemberUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, ThreadMembersUpdate) error:
-		return threadMembersUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, EntitlementCreate) error:
-		return entitlementCreateHandler{f: v}, nil
-
-	case func(*Fetcher, EntitlementUpdate) error:
-		return entitlementUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, EntitlementDelete) error:
-		return entitlementDeleteHandler{f: v}, nil
-
-	case func(*Fetcher, GuildCreate) error:
-		return guildCreateHandler{f: v}, nil
-
-	case func(*Fetcher, GuildUpdate) error:
-		return guildUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, GuildDelete) error:
-		return guildDeleteHandler{f: v}, nil
-
-	case func(*Fetcher, GuildBanAdd) error:
-		return guildBanAddHandler{f: v}, nil
-
-	case func(*Fetcher, GuildBanRemove) error:
-		return guildBanRemoveHandler{f: v}, nil
-
-	case func(*Fetcher, GuildEmojisUpdate) error:
-		return guildEmojisUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, GuildStickersUpdate) error:
-		return guildStickersUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, GuildMemberAdd) error:
-		return guildMemberAddHandler{f: v}, nil
-
-	case func(*Fetcher, GuildMemberUpdate) error:
-		return guildMemberUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, GuildMemberRemove) error:
-		return guildMemberRemoveHandler{f: v}, nil
-
-	case func(*Fetcher, GuildMembersChunk) error:
-		return guildMembersChunkHandler{f: v}, nil
-
-	case func(*Fetcher, GuildRoleCreate) error:
-		return guildRoleCreateHandler{f: v}, nil
-
-	case func(*Fetcher, GuildRoleUpdate) error:
-		return guildRoleUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, GuildRoleDelete) error:
-		return guildRoleDeleteHandler{f: v}, nil
-
-	case func(*Fetcher, GuildScheduledEventCreate) error:
-		return guildScheduledEventCreateHandler{f: v}, nil
-
-	case func(*Fetcher, GuildScheduledEventUpdate) error:
-		return guildScheduledEventUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, GuildScheduledEventDelete) error:
-		return guildScheduledEventDeleteHandler{f: v}, nil
-
-	case func(*Fetcher, GuildScheduledEventUserAddEvent) error:
-		return guildScheduledEventUserAddEventHandler{f: v}, nil
-
-	case func(*Fetcher, GuildScheduledEventUserRemoveEvent) error:
-		return guildScheduledEventUserRemoveEventHandler{f: v}, nil
-
-	case func(*Fetcher, IntegrationCreate) error:
-		return integrationCreateHandler{f: v}, nil
-
-	case func(*Fetcher, IntegrationUpdate) error:
-		return integrationUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, IntegrationDelete) error:
-		return integrationDeleteHandler{f: v}, nil
-
-	case func(*Fetcher, InviteCreate) error:
-		return inviteCreateHandler{f: v}, nil
-
-	case func(*Fetcher, InviteDelete) error:
-		return inviteDeleteHandler{f: v}, nil
-
-	case func(*Fetcher, MessageCreate) error:
-		return messageCreateHandler{f: v}, nil
-
-	case func(*Fetcher, MessageUpdate) error:
-		return messageUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, MessageDelete) error:
-		return messageDeleteHandler{f: v}, nil
-
-	case func(*Fetcher, MessageDeleteBulk) error:
-		return messageDeleteBulkHandler{f: v}, nil
-
-	case func(*Fetcher, MessageReactionAdd) error:
-		return messageReactionAddHandler{f: v}, nil
-
-	case func(*Fetcher, MessageReactionRemove) error:
-		return messageReactionRemoveHandler{f: v}, nil
-
-	case func(*Fetcher, MessageReactionRemoveAll) error:
-		return messageReactionRemoveAllHandler{f: v}, nil
-
-	case func(*Fetcher, MessageReactionRemoveEmoji) error:
-		return messageReactionRemoveEmojiHandler{f: v}, nil
-
-	case func(*Fetcher, PresenceUpdate) error:
-		return presenceUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, StageInstanceCreate) error:
-		return stageInstanceCreateHandler{f: v}, nil
-
-	case func(*Fetcher, StageInstanceUpdate) error:
-		return stageInstanceUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, StageInstanceDelete) error:
-		return stageInstanceDeleteHandler{f: v}, nil
-
-	case func(*Fetcher, TypingStart) error:
-		return typingStartHandler{f: v}, nil
-
-	case func(*Fetcher, UserUpdate) error:
-		return userUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, VoiceStateUpdate) error:
-		return voiceStateUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, VoiceServerUpdate) error:
-		return voiceServerUpdateHandler{f: v}, nil
-
-	case func(*Fetcher, WebhooksUpdate) error:
-		return webhooksUpdateHandler{f: v}, nil
-	default:
-		return nil, fmt.Errorf("unknown event")
-	}
-}
@@ -1,8 +1,8 @@
 package godiscord
 
 type Heartbeat struct {
-	OpCode       int  `json:"op"`
-	LastSequence *int `json:"d"`
+	OpCode       int     `json:"op"`
+	LastSequence *uint64 `json:"d"`
 }
 
 const (
@@ -24,7 +24,7 @@ type IdentifyPayload struct {
 	Compress       bool       `json:"compress,omitempty"`
 	LargeThreshold int        `json:"large_threshold,omitempty"`
 	Shard          []int      `json:"shard,omitempty"`
-	Presence       *Presence  `json:"presence"`
+	Presence       *Presence  `json:"presence,omitempty"`
 	Intents        int        `json:"intents"`
 }
 
@@ -38,3 +38,34 @@ type ResumePayload struct {
 	SessionID    string `json:"session_id"`
 	LastSequence uint64 `json:"seq"`
 }
+
+type UpdatePresence struct {
+	OpCode  int      `json:"op"`
+	Payload Presence `json:"d"`
+}
+
+type UpdateVoiceState struct {
+	OpCode  int                     `json:"op"`
+	Payload UpdateVoiceStatePayload `json:"d"`
+}
+
+type UpdateVoiceStatePayload struct {
+	GuildID   string  `json:"guild_id"`
+	ChannelID *string `json:"channel_id"` // Channel to join, nil to leave the voice channel.
+	SelfMute  bool    `json:"self_mute"`
+	SelfDeaf  bool    `json:"self_deaf"`
+}
+
+type RequestGuildMembers struct {
+	OpCode  int                        `json:"op"`
+	Payload RequestGuildMembersPayload `json:"d"`
+}
+
+type RequestGuildMembersPayload struct {
+	GuildID   string   `json:"guild_id"`
+	Query     *string  `json:"query,omitempty"`     // Prefix the usernames must start with, "" for all members. Either this or UserIDs.
+	Limit     int      `json:"limit"`               // Max number of members to return, 0 for no limit when querying all members.
+	Presences bool     `json:"presences,omitempty"` // Whether to include the presences of the members.
+	UserIDs   []string `json:"user_ids,omitempty"`  // Members to fetch.
+	Nonce     string   `json:"nonce,omitempty"`     // Sent back in the chunks, to tell which request they belong to.
+}
@@ -0,0 +1,258 @@
+package godiscord
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEventDecoders(t *testing.T) {
+	const (
+		guild       = "81384788765712384"
+		user        = `{"id": "80351110224678912", "username": "nelly", "discriminator": "0", "avatar": "8342729096ea3675442027381ff50dfe", "global_name": "Nelly"}`
+		member      = `{"user": ` + user + `, "nick": null, "roles": ["41771983423143936"], "joined_at": "2015-04-26T06:26:56.936000+00:00", "deaf": false, "mute": false, "flags": 0}`
+		thread      = `{"id": "41771983423143937", "type": 11, "guild_id": "` + guild + `", "parent_id": "41771983423143936", "owner_id": "80351110224678912", "name": "thread", "message_count": 0, "member_count": 1, "thread_metadata": {"archived": false, "auto_archive_duration": 1440, "archive_timestamp": "2023-01-01T00:00:00+00:00", "locked": false}}`
+		rule        = `{"id": "969707018069872670", "guild_id": "` + guild + `", "name": "Keyword Filter 1", "creator_id": "80351110224678912", "event_type": 1, "trigger_type": 1, "trigger_metadata": {"keyword_filter": ["cat*", "*dog"], "regex_patterns": ["(b|c)at"], "allow_list": []}, "actions": [{"type": 1, "metadata": {"custom_message": "Please keep it civil"}}], "enabled": true, "exempt_roles": [], "exempt_channels": []}`
+		event       = `{"id": "941580012541014045", "guild_id": "` + guild + `", "channel_id": "41771983423143936", "creator_id": "80351110224678912", "name": "Movie night", "scheduled_start_time": "2023-01-01T20:00:00+00:00", "privacy_level": 2, "status": 1, "entity_type": 2, "entity_id": null}`
+		sound       = `{"name": "quack", "sound_id": "1106714396018884649", "volume": 1.0, "emoji_id": null, "emoji_name": "🦆", "guild_id": "` + guild + `", "available": true}`
+		stage       = `{"id": "840647391636226060", "guild_id": "` + guild + `", "channel_id": "733488538393510049", "topic": "Testing Testing, 123", "privacy_level": 2, "discoverable_disabled": false, "guild_scheduled_event_id": "947656305244532806"}`
+		entitlement = `{"id": "1019653849998299136", "sku_id": "1019475255913222144", "application_id": "1019370614521200640", "user_id": "80351110224678912", "type": 8, "deleted": false, "starts_at": "2022-09-14T17:00:18.704163+00:00", "ends_at": "2022-10-14T17:00:18.704163+00:00", "guild_id": "` + guild + `"}`
+		integration = `{"id": "1033102993917038626", "name": "bot", "type": "discord", "enabled": true, "account": {"id": "1019370614521200640", "name": "bot"}, "application": {"id": "1019370614521200640", "name": "bot", "icon": null, "description": ""}, "scopes": ["bot", "applications.commands"], "user": ` + user + `, "guild_id": "` + guild + `"}`
+		message     = `{"id": "1029011297598181376", "channel_id": "41771983423143936", "guild_id": "` + guild + `", "author": ` + user + `, "member": {"roles": [], "joined_at": "2015-04-26T06:26:56.936000+00:00", "deaf": false, "mute": false, "flags": 0}, "content": "hello", "timestamp": "2022-10-12T07:00:00.000000+00:00", "edited_timestamp": null, "tts": false, "mention_everyone": false, "mentions": [], "mention_roles": [], "attachments": [], "embeds": [], "pinned": false, "type": 0}`
+		voiceState  = `{"guild_id": "` + guild + `", "channel_id": "733488538393510049", "user_id": "80351110224678912", "member": ` + member + `, "session_id": "90326bd25d71d39b9ef95b299e3872ff", "deaf": false, "mute": false, "self_deaf": false, "self_mute": true, "self_video": false, "suppress": false, "request_to_speak_timestamp": null}`
+		guildObject = `{"id": "` + guild + `", "name": "Discord API", "icon": null, "owner_id": "80351110224678912", "afk_channel_id": null, "afk_timeout": 300, "verification_level": 1, "default_message_notifications": 1, "explicit_content_filter": 2, "roles": [{"id": "` + guild + `", "name": "@everyone", "color": 0, "hoist": false, "position": 0, "permissions": "104324673", "managed": false, "mentionable": false, "flags": 0}], "emojis": [], "features": ["COMMUNITY"], "mfa_level": 0, "system_channel_flags": 0, "premium_tier": 0, "preferred_locale": "en-US", "nsfw_level": 0, "stickers": []}`
+	)
+
+	tests := map[string]struct {
+		data  string
+		want  DispatchEvent // Zero value of the type the event decodes to.
+		guild string
+	}{
+		// The session events aren't tied to a guild.
+		"READY": {
+			`{"v": 10, "user": ` + user + `, "guilds": [{"id": "` + guild + `", "unavailable": true}], "session_id": "d4f7c6cbc7e1e4e3f6c5b1f3b2a9e8d7", "resume_gateway_url": "wss://gateway-us-east1-b.discord.gg", "shard": [0, 1], "application": {"id": "1019370614521200640", "flags": 0}}`,
+			Ready{}, "",
+		},
+		"RESUMED": {`{"_trace": ["[\"gateway-prd-us-east1-b-0568\",{\"micros\":0}]"]}`, Resumed{}, ""},
+		"APPLICATION_COMMAND_PERMISSIONS_UPDATE": {
+			`{"id": "1019370614521200640", "application_id": "1019370614521200640", "guild_id": "` + guild + `", "permissions": [{"id": "41771983423143936", "type": 1, "permission": true}]}`,
+			ApplicationCommandPermissionsUpdate{}, guild,
+		},
+		"AUTO_MODERATION_RULE_CREATE": {rule, AutoModerationRuleCreate{}, guild},
+		"AUTO_MODERATION_RULE_UPDATE": {rule, AutoModerationRuleUpdate{}, guild},
+		"AUTO_MODERATION_RULE_DELETE": {rule, AutoModerationRuleDelete{}, guild},
+		"AUTO_MODERATION_ACTION_EXECUTION": {
+			`{"guild_id": "` + guild + `", "action": {"type": 1, "metadata": {}}, "rule_id": "969707018069872670", "rule_trigger_type": 1, "user_id": "80351110224678912", "channel_id": "41771983423143936", "message_id": "1029011297598181376", "content": "cats", "matched_keyword": "cat*", "matched_content": "cats"}`,
+			AutoModerationActionExecution{}, guild,
+		},
+		"CHANNEL_CREATE": {
+			`{"id": "41771983423143936", "type": 0, "guild_id": "` + guild + `", "position": 6, "permission_overwrites": [], "name": "general", "topic": null, "nsfw": false, "last_message_id": null, "rate_limit_per_user": 0, "parent_id": null}`,
+			ChannelCreate{}, guild,
+		},
+		"CHANNEL_UPDATE": {
+			`{"id": "41771983423143936", "type": 0, "guild_id": "` + guild + `", "position": 6, "permission_overwrites": [], "name": "general", "topic": "Chat", "nsfw": false, "last_message_id": "1029011297598181376", "rate_limit_per_user": 2, "parent_id": null}`,
+			ChannelUpdate{}, guild,
+		},
+		"CHANNEL_DELETE": {
+			// Channels outside of a guild have no guild.
+			`{"id": "319674150115610528", "type": 1, "last_message_id": "3343820033257021450", "recipients": [` + user + `]}`,
+			ChannelDelete{}, "",
+		},
+		"CHANNEL_PINS_UPDATE": {
+			`{"guild_id": "` + guild + `", "channel_id": "41771983423143936", "last_pin_timestamp": "2022-10-12T07:00:00+00:00"}`,
+			ChannelPinsUpdate{}, guild,
+		},
+		"THREAD_CREATE": {thread, ThreadCreate{}, guild},
+		"THREAD_UPDATE": {thread, ThreadUpdate{}, guild},
+		"THREAD_DELETE": {
+			`{"id": "41771983423143937", "type": 11, "guild_id": "` + guild + `", "parent_id": "41771983423143936"}`,
+			ThreadDelete{}, guild,
+		},
+		"THREAD_LIST_SYNC": {
+			`{"guild_id": "` + guild + `", "channel_ids": ["41771983423143936"], "threads": [` + thread + `], "members": [{"id": "41771983423143937", "user_id": "80351110224678912", "join_timestamp": "2023-01-01T00:00:00+00:00", "flags": 1}]}`,
+			ThreadListSync{}, guild,
+		},
+		"THREAD_MEMBER_UPDATE": {
+			`{"id": "41771983423143937", "user_id": "80351110224678912", "join_timestamp": "2023-01-01T00:00:00+00:00", "flags": 1, "guild_id": "` + guild + `"}`,
+			ThreadMemberUpdate{}, guild,
+		},
+		"THREAD_MEMBERS_UPDATE": {
+			`{"id": "41771983423143937", "guild_id": "` + guild + `", "member_count": 2, "added_members": [{"id": "41771983423143937", "user_id": "80351110224678912", "join_timestamp": "2023-01-01T00:00:00+00:00", "flags": 1, "member": ` + member + `}], "removed_member_ids": ["1019370614521200640"]}`,
+			ThreadMembersUpdate{}, guild,
+		},
+		"ENTITLEMENT_CREATE": {entitlement, EntitlementCreate{}, guild},
+		"ENTITLEMENT_UPDATE": {entitlement, EntitlementUpdate{}, guild},
+		"ENTITLEMENT_DELETE": {
+			// Entitlements of users have no guild.
+			`{"id": "1019653849998299136", "sku_id": "1019475255913222144", "application_id": "1019370614521200640", "user_id": "80351110224678912", "type": 8, "deleted": true}`,
+			EntitlementDelete{}, "",
+		},
+		"GUILD_CREATE": {
+			`{"id": "` + guild + `", "name": "Discord API", "owner_id": "80351110224678912", "roles": [], "emojis": [], "features": [], "stickers": [], "joined_at": "2023-01-01T00:00:00+00:00", "large": false, "member_count": 1, "voice_states": [], "members": [` + member + `], "channels": [], "threads": [], "presences": [], "stage_instances": [], "guild_scheduled_events": [], "soundboard_sounds": []}`,
+			GuildCreate{}, guild,
+		},
+		"GUILD_UPDATE": {guildObject, GuildUpdate{}, guild},
+		"GUILD_DELETE": {`{"id": "` + guild + `", "unavailable": true}`, GuildDelete{}, guild},
+		"GUILD_AUDIT_LOG_ENTRY_CREATE": {
+			`{"id": "1067478880853565480", "user_id": "80351110224678912", "target_id": "80351110224678912", "action_type": 24, "changes": [{"key": "nick", "new_value": "nelly"}], "reason": "Renamed", "guild_id": "` + guild + `"}`,
+			GuildAuditLogEntryCreate{}, guild,
+		},
+		"GUILD_BAN_ADD":             {`{"guild_id": "` + guild + `", "user": ` + user + `}`, GuildBanAdd{}, guild},
+		"GUILD_BAN_REMOVE":          {`{"guild_id": "` + guild + `", "user": ` + user + `}`, GuildBanRemove{}, guild},
+		"GUILD_INTEGRATIONS_UPDATE": {`{"guild_id": "` + guild + `"}`, GuildIntegrationsUpdate{}, guild},
+		"GUILD_EMOJIS_UPDATE": {
+			`{"guild_id": "` + guild + `", "emojis": [{"id": "41771983429993937", "name": "LUL", "roles": [], "require_colons": true, "managed": false, "animated": false, "available": true}]}`,
+			GuildEmojisUpdate{}, guild,
+		},
+		"GUILD_STICKERS_UPDATE": {
+			`{"guild_id": "` + guild + `", "stickers": [{"id": "749054660769218631", "name": "Wave", "tags": "wumpus, hello", "type": 2, "format_type": 3, "description": "Wumpus waves hello", "available": true, "guild_id": "` + guild + `"}]}`,
+			GuildStickersUpdate{}, guild,
+		},
+		"GUILD_MEMBER_ADD": {
+			`{"guild_id": "` + guild + `", "user": ` + user + `, "nick": null, "roles": [], "joined_at": "2023-01-01T00:00:00+00:00", "deaf": false, "mute": false, "flags": 0, "pending": true}`,
+			GuildMemberAdd{}, guild,
+		},
+		"GUILD_MEMBER_UPDATE": {
+			`{"guild_id": "` + guild + `", "roles": ["41771983423143936"], "user": ` + user + `, "nick": "nelly", "avatar": null, "joined_at": "2015-04-26T06:26:56.936000+00:00", "premium_since": null, "deaf": false, "mute": false, "pending": false, "communication_disabled_until": null}`,
+			GuildMemberUpdate{}, guild,
+		},
+		"GUILD_MEMBER_REMOVE": {`{"guild_id": "` + guild + `", "user": ` + user + `}`, GuildMemberRemove{}, guild},
+		"GUILD_MEMBERS_CHUNK": {
+			`{"guild_id": "` + guild + `", "members": [` + member + `], "chunk_index": 0, "chunk_count": 1, "not_found": ["1019370614521200640"], "nonce": "4fdd2c1c3e3b8d3ee1da1f6b4e8b0bd4"}`,
+			GuildMembersChunk{}, guild,
+		},
+		"GUILD_ROLE_CREATE": {
+			`{"guild_id": "` + guild + `", "role": {"id": "41771983423143936", "name": "WE DEM BOYZZ!!!!!!", "color": 3447003, "hoist": true, "icon": "cf3ced8600b777c9486c5d8d1f4f8c8a", "position": 1, "permissions": "66321471", "managed": false, "mentionable": false, "flags": 0}}`,
+			GuildRoleCreate{}, guild,
+		},
+		"GUILD_ROLE_UPDATE": {
+			`{"guild_id": "` + guild + `", "role": {"id": "41771983423143936", "name": "WE DEM BOYZZ!!!!!!", "color": 3447003, "hoist": false, "position": 2, "permissions": "66321471", "managed": false, "mentionable": true, "flags": 0}}`,
+			GuildRoleUpdate{}, guild,
+		},
+		"GUILD_ROLE_DELETE":            {`{"guild_id": "` + guild + `", "role_id": "41771983423143936"}`, GuildRoleDelete{}, guild},
+		"GUILD_SCHEDULED_EVENT_CREATE": {event, GuildScheduledEventCreate{}, guild},
+		"GUILD_SCHEDULED_EVENT_UPDATE": {event, GuildScheduledEventUpdate{}, guild},
+		"GUILD_SCHEDULED_EVENT_DELETE": {event, GuildScheduledEventDelete{}, guild},
+		"GUILD_SCHEDULED_EVENT_USER_ADD": {
+			`{"guild_scheduled_event_id": "941580012541014045", "user_id": "80351110224678912", "guild_id": "` + guild + `"}`,
+			GuildScheduledEventUserAdd{}, guild,
+		},
+		"GUILD_SCHEDULED_EVENT_USER_REMOVE": {
+			`{"guild_scheduled_event_id": "941580012541014045", "user_id": "80351110224678912", "guild_id": "` + guild + `"}`,
+			GuildScheduledEventUserRemove{}, guild,
+		},
+		"GUILD_SOUNDBOARD_SOUND_CREATE": {sound, GuildSoundboardSoundCreate{}, guild},
+		"GUILD_SOUNDBOARD_SOUND_UPDATE": {sound, GuildSoundboardSoundUpdate{}, guild},
+		"GUILD_SOUNDBOARD_SOUND_DELETE": {
+			`{"sound_id": "1106714396018884649", "guild_id": "` + guild + `"}`,
+			GuildSoundboardSoundDelete{}, guild,
+		},
+		"GUILD_SOUNDBOARD_SOUNDS_UPDATE": {
+			`{"soundboard_sounds": [` + sound + `], "guild_id": "` + guild + `"}`,
+			GuildSoundboardSoundsUpdate{}, guild,
+		},
+		"SOUNDBOARD_SOUNDS":  {`{"soundboard_sounds": [` + sound + `], "guild_id": "` + guild + `"}`, SoundboardSounds{}, guild},
+		"INTEGRATION_CREATE": {integration, IntegrationCreate{}, guild},
+		"INTEGRATION_UPDATE": {integration, IntegrationUpdate{}, guild},
+		"INTEGRATION_DELETE": {
+			`{"id": "1033102993917038626", "guild_id": "` + guild + `", "application_id": "1019370614521200640"}`,
+			IntegrationDelete{}, guild,
+		},
+		"INVITE_CREATE": {
+			`{"channel_id": "41771983423143936", "code": "0vCdhLbwjZZTWZLD", "created_at": "2023-01-01T00:00:00+00:00", "guild_id": "` + guild + `", "inviter": ` + user + `, "max_age": 86400, "max_uses": 0, "temporary": false, "uses": 0}`,
+			InviteCreate{}, guild,
+		},
+		"INVITE_DELETE": {
+			`{"channel_id": "41771983423143936", "guild_id": "` + guild + `", "code": "0vCdhLbwjZZTWZLD"}`,
+			InviteDelete{}, guild,
+		},
+		"MESSAGE_CREATE": {message, MessageCreate{}, guild},
+		"MESSAGE_UPDATE": {message, MessageUpdate{}, guild},
+		"MESSAGE_DELETE": {
+			`{"id": "1029011297598181376", "channel_id": "41771983423143936", "guild_id": "` + guild + `"}`,
+			MessageDelete{}, guild,
+		},
+		"MESSAGE_DELETE_BULK": {
+			`{"ids": ["1029011297598181376", "1029011297598181377"], "channel_id": "41771983423143936", "guild_id": "` + guild + `"}`,
+			MessageDeleteBulk{}, guild,
+		},
+		"MESSAGE_REACTION_ADD": {
+			`{"user_id": "80351110224678912", "channel_id": "41771983423143936", "message_id": "1029011297598181376", "guild_id": "` + guild + `", "member": ` + member + `, "emoji": {"id": null, "name": "🔥"}, "message_author_id": "1019370614521200640", "burst": false, "type": 0}`,
+			MessageReactionAdd{}, guild,
+		},
+		"MESSAGE_REACTION_REMOVE": {
+			`{"user_id": "80351110224678912", "channel_id": "41771983423143936", "message_id": "1029011297598181376", "guild_id": "` + guild + `", "emoji": {"id": null, "name": "🔥"}, "burst": false, "type": 0}`,
+			MessageReactionRemove{}, guild,
+		},
+		"MESSAGE_REACTION_REMOVE_ALL": {
+			`{"channel_id": "41771983423143936", "message_id": "1029011297598181376", "guild_id": "` + guild + `"}`,
+			MessageReactionRemoveAll{}, guild,
+		},
+		"MESSAGE_REACTION_REMOVE_EMOJI": {
+			`{"channel_id": "41771983423143936", "guild_id": "` + guild + `", "message_id": "1029011297598181376", "emoji": {"id": "41771983429993937", "name": "LUL"}}`,
+			MessageReactionRemoveEmoji{}, guild,
+		},
+		"MESSAGE_POLL_VOTE_ADD": {
+			`{"user_id": "80351110224678912", "channel_id": "41771983423143936", "message_id": "1029011297598181376", "guild_id": "` + guild + `", "answer_id": 1}`,
+			MessagePollVoteAdd{}, guild,
+		},
+		"MESSAGE_POLL_VOTE_REMOVE": {
+			`{"user_id": "80351110224678912", "channel_id": "41771983423143936", "message_id": "1029011297598181376", "guild_id": "` + guild + `", "answer_id": 1}`,
+			MessagePollVoteRemove{}, guild,
+		},
+		"PRESENCE_UPDATE": {
+			`{"user": {"id": "80351110224678912"}, "guild_id": "` + guild + `", "status": "online", "activities": [{"name": "Rocket League", "type": 0, "created_at": 1507665886081}], "client_status": {"desktop": "online"}}`,
+			PresenceUpdate{}, guild,
+		},
+		"INTERACTION_CREATE": {
+			`{"id": "1050124312440877207", "application_id": "1019370614521200640", "type": 2, "data": {"id": "1050109473403981844", "name": "ping", "type": 1}, "guild_id": "` + guild + `", "channel_id": "41771983423143936", "member": ` + member + `, "token": "aW50ZXJhY3Rpb246MTA1MDEyNDMxMjQ0MDg3NzIwNw", "version": 1, "app_permissions": "1071698660929", "locale": "en-US", "guild_locale": "en-US"}`,
+			InteractionCreate{}, guild,
+		},
+		"STAGE_INSTANCE_CREATE": {stage, StageInstanceCreate{}, guild},
+		"STAGE_INSTANCE_UPDATE": {stage, StageInstanceUpdate{}, guild},
+		"STAGE_INSTANCE_DELETE": {stage, StageInstanceDelete{}, guild},
+		"TYPING_START": {
+			`{"channel_id": "41771983423143936", "guild_id": "` + guild + `", "user_id": "80351110224678912", "timestamp": 1665558000, "member": ` + member + `}`,
+			TypingStart{}, guild,
+		},
+		// The bot's own user isn't tied to a guild.
+		"USER_UPDATE":        {user, UserUpdate{}, ""},
+		"VOICE_STATE_UPDATE": {voiceState, VoiceStateUpdate{}, guild},
+		"VOICE_SERVER_UPDATE": {
+			`{"token": "my_token", "guild_id": "` + guild + `", "endpoint": "sweetvoiceserver.discord.media:443"}`,
+			VoiceServerUpdate{}, guild,
+		},
+		"WEBHOOKS_UPDATE": {`{"guild_id": "` + guild + `", "channel_id": "41771983423143936"}`, WebhooksUpdate{}, guild},
+	}
+
+	for name := range eventDecoders {
+		if _, ok := tests[name]; !ok {
+			t.Errorf("no fixture for %s", name)
+		}
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			decode, ok := eventDecoders[name]
+			if !ok {
+				t.Fatalf("no decoder")
+			}
+
+			ev, err := decode([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+
+			if got, want := reflect.TypeOf(ev), reflect.TypeOf(tt.want); got != want {
+				t.Errorf("decoded to %v, want %v", got, want)
+			}
+
+			if got := ev.EventName(); got != name {
+				t.Errorf("EventName() = %q", got)
+			}
+
+			if got := ev.guild(); got != tt.guild {
+				t.Errorf("guild() = %q, want %q", got, tt.guild)
+			}
+		})
+	}
+}
@@ -1,91 +1,130 @@
 package godiscord
 
+import (
+	"context"
+	"errors"
+)
+
 // TODO: Fetcher is not really the name I'm looking for... Context? Taken by stdlib tho.
 
-func newFetcher(guildEvent GuildCreate, restClient *restClient) *Fetcher {
-	fetcher := Fetcher{
-		guildID:         guildEvent.Guild.ID,
-		membersByID:     make(map[string]GuildMember),
-		channelsByID:    make(map[string]Channel),
-		threadsByID:     make(map[string]Channel),
-		voiceStatesByID: make(map[string]VoiceState),
-		restClient:      restClient,
-	}
+// errNoGuild is returned by the actions of a guild, when the fetcher is given for an event outside of a guild.
+var errNoGuild = errors.New("not in a guild")
 
-	for _, voiceState := range guildEvent.VoiceStates {
-		fetcher.voiceStatesByID[voiceState.UserID] = voiceState
+func newFetcher(guildID string, restClient *restClient, shard *Shard, cache StateCache) *Fetcher {
+	return &Fetcher{
+		guildID:    guildID,
+		cache:      cache,
+		restClient: restClient,
+		shard:      shard,
 	}
+}
 
-	for _, member := range guildEvent.Members {
-		member := member
-		if member.User == nil {
-			continue
-		}
+// Fetcher is the view of a guild given to handlers, with its cached state and the means to act on it.
+// Handlers of events outside of a guild, such as direct messages, get a Fetcher without a guild, see Fetcher.GuildID.
+//
+// The getters of the cached state read from the bot's StateCache, and are safe to call from any goroutine.
+// They return copies taken at the time of the call, see Bot.ListGuilds.
+type Fetcher struct {
+	guildID    string
+	cache      StateCache
+	restClient *restClient
+	shard      *Shard
+	ctx        context.Context // Context of the run of the bot, nil if not given to a handler.
+}
 
-		fetcher.membersByID[member.User.ID] = member
-		// Discord does not provide member in voice states for GUILD_CREATE for whatever reason.
-		// So we fill it from received members.
-		if voiceState, ok := fetcher.voiceStatesByID[member.User.ID]; ok {
-			voiceState.Member = &member
-			fetcher.voiceStatesByID[member.User.ID] = voiceState
-		}
-	}
+// GuildID returns the id of the guild, or an empty string for events outside of a guild, such as direct messages.
+// Without a guild, the getters of the guild's cached state return nothing.
+func (f *Fetcher) GuildID() string {
+	return f.guildID
+}
 
-	for _, channel := range guildEvent.Channels {
-		fetcher.channelsByID[channel.ID] = channel
+// Context returns the context of the run of the bot, which is done when the bot is stopped. The requests of the
+// methods without a context argument are made with it, so they are canceled on shutdown.
+func (f *Fetcher) Context() context.Context {
+	if f.ctx == nil {
+		return context.Background()
 	}
 
-	for _, thread := range guildEvent.Threads {
-		fetcher.threadsByID[thread.ID] = thread
-	}
+	return f.ctx
+}
 
-	return &fetcher
+// withContext returns a copy of the fetcher using ctx as its context.
+func (f *Fetcher) withContext(ctx context.Context) *Fetcher {
+	f2 := *f
+	f2.ctx = ctx
+	return &f2
 }
 
-type Fetcher struct {
-	guildID         string
-	membersByID     map[string]GuildMember
-	channelsByID    map[string]Channel
-	threadsByID     map[string]Channel
-	voiceStatesByID map[string]VoiceState
-	restClient      *restClient
+// Shard returns the shard receiving the events of the guild.
+func (f *Fetcher) Shard() *Shard {
+	return f.shard
 }
 
 func (f *Fetcher) SendContent(channelID, content string) (*MessageCreateResponse, error) {
-	return f.restClient.MessageSend(channelID, MessageCreateRequest{
+	return f.SendContentContext(f.Context(), channelID, content)
+}
+
+// SendContentContext is like SendContent, but the request is canceled when ctx is done.
+func (f *Fetcher) SendContentContext(ctx context.Context, channelID, content string) (*MessageCreateResponse, error) {
+	return f.restClient.MessageSend(ctx, channelID, MessageCreateRequest{
 		Content: content,
 	})
 }
 
 func (f *Fetcher) SendEmbeds(channelID string, embeds []Embed) (*MessageCreateResponse, error) {
-	return f.restClient.MessageSend(channelID, MessageCreateRequest{
+	return f.SendEmbedsContext(f.Context(), channelID, embeds)
+}
+
+// SendEmbedsContext is like SendEmbeds, but the request is canceled when ctx is done.
+func (f *Fetcher) SendEmbedsContext(ctx context.Context, channelID string, embeds []Embed) (*MessageCreateResponse, error) {
+	return f.restClient.MessageSend(ctx, channelID, MessageCreateRequest{
 		Embeds: embeds,
 	})
 }
 
 func (f *Fetcher) CreateThread(channelID, messageID string, req CreateThreadRequest) (*CreateThreadResponse, error) {
-	return f.restClient.CreateThread(channelID, messageID, req)
+	return f.CreateThreadContext(f.Context(), channelID, messageID, req)
+}
+
+// CreateThreadContext is like CreateThread, but the request is canceled when ctx is done.
+func (f *Fetcher) CreateThreadContext(ctx context.Context, channelID, messageID string, req CreateThreadRequest) (*CreateThreadResponse, error) {
+	return f.restClient.CreateThread(ctx, channelID, messageID, req)
 }
 
 func (f *Fetcher) Do(path, method string, res any, resp any) error {
-	return f.restClient.do(path, method, res, resp)
+	return f.DoContext(f.Context(), path, method, res, resp)
+}
+
+// DoContext is like Do, but the request is canceled when ctx is done.
+// Cancellation also applies while the request is queued behind a rate limit.
+func (f *Fetcher) DoContext(ctx context.Context, path, method string, res any, resp any) error {
+	return f.restClient.do(ctx, method, path, res, resp)
 }
 
 func (f *Fetcher) CreateChannel(req CreateChannelRequest) error {
-	return f.restClient.CreateChannel(f.guildID, req)
+	return f.CreateChannelContext(f.Context(), req)
+}
+
+// CreateChannelContext is like CreateChannel, but the request is canceled when ctx is done.
+func (f *Fetcher) CreateChannelContext(ctx context.Context, req CreateChannelRequest) error {
+	if f.guildID == "" {
+		return errNoGuild
+	}
+
+	return f.restClient.CreateChannel(ctx, f.guildID, req)
 }
 
 func (f *Fetcher) GetVoiceStates() []VoiceState {
-	return Values(f.voiceStatesByID)
+	return f.cache.VoiceStates(f.guildID)
 }
 
 func (f *Fetcher) GetMembers() []GuildMember {
-	return Values(f.membersByID)
+	return f.cache.Members(f.guildID)
 }
 
 func (f *Fetcher) GetMembersByIDs(userIDs ...string) (members []GuildMember) {
 	for _, id := range userIDs {
-		member, ok := f.membersByID[id]
+		member, ok := f.cache.Member(f.guildID, id)
 		if ok {
 			members = append(members, member)
 		}
@@ -94,20 +133,31 @@ func (f *Fetcher) GetMembersByIDs(userIDs ...string) (members []GuildMember) {
 	return members
 }
 
+func (f *Fetcher) GetRoles() []Role {
+	return f.cache.Roles(f.guildID)
+}
+
+func (f *Fetcher) GetEmojis() []Emoji {
+	return f.cache.Emojis(f.guildID)
+}
+
+// GetPresences returns the cached presences of the members, which are only received with IntentGuildPresences.
+func (f *Fetcher) GetPresences() []PresenceUpdate {
+	return f.cache.Presences(f.guildID)
+}
+
 func (f *Fetcher) GetChannels() []Channel {
-	return Values(f.channelsByID)
+	return f.cache.Channels(f.guildID)
 }
 
 func (f *Fetcher) GetChannelByID(channelID string) (Channel, bool) {
-	c, ok := f.channelsByID[channelID]
-	return c, ok
+	return f.cache.Channel(f.guildID, channelID)
 }
 
 // TODO: Might want to just merge channels and threads, as they are the same type anyway.
 
 func (f *Fetcher) GetThreadByID(threadID string) (Channel, bool) {
-	c, ok := f.threadsByID[threadID]
-	return c, ok
+	return f.cache.Thread(f.guildID, threadID)
 }
 
 func (f *Fetcher) GetChannelByName(name string) (Channel, bool) {
@@ -125,7 +175,7 @@ func (f *Fetcher) GetChannelByName(name string) (Channel, bool) {
 
 func (f *Fetcher) GetChannelsByIDs(channelIDs ...string) (channels []Channel) {
 	for _, id := range channelIDs {
-		channel, ok := f.channelsByID[id]
+		channel, ok := f.cache.Channel(f.guildID, id)
 		if ok {
 			channels = append(channels, channel)
 		}
@@ -0,0 +1,48 @@
+package godiscord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFetcherRequestsAreCanceledWithTheRun(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	b := newTestBot()
+	b.restClient = newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-unblock:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := b.fetcherFor(ctx, nil, "")
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := f.SendContent("123456789012345678", "hello")
+		errs <- err
+	}()
+	go func() {
+		errs <- f.Responder(Interaction{ID: "223456789012345678", Token: "token"}).ReplyContent("hello")
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got error %v, want the request canceled", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("request wasn't canceled with the run")
+		}
+	}
+}
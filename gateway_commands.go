@@ -0,0 +1,45 @@
+package godiscord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// UpdatePresence changes the status and activities of the bot on all shards.
+// status is one of the UserStatus constants.
+func (b *Bot) UpdatePresence(status string, activities ...*Activity) error {
+	return b.UpdatePresenceContext(context.Background(), status, activities...)
+}
+
+// UpdatePresenceContext is like UpdatePresence, but gives up waiting for the gateway rate limit when ctx is done.
+func (b *Bot) UpdatePresenceContext(ctx context.Context, status string, activities ...*Activity) error {
+	switch status {
+	case UserStatusOnline, UserStatusDND, UserStatusIdle, UserStatusInvisible, UserStatusOffline:
+	default:
+		return fmt.Errorf("invalid status %q", status)
+	}
+
+	presence := &Presence{
+		Activities: activities,
+		Status:     status,
+	}
+
+	// Shards that reconnect later on identify with the new presence.
+	b.mu.Lock()
+	b.presence = presence
+	b.mu.Unlock()
+
+	var errs []error
+	for _, shard := range b.shardManager.shards {
+		err := shard.send(ctx, UpdatePresence{
+			OpCode:  OpCodePresenceUpdate,
+			Payload: *presence,
+		})
+		if err != nil && !errors.Is(err, errShardNotConnected) {
+			errs = append(errs, fmt.Errorf("failed to update presence on shard %d: %w", shard.id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
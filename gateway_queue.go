@@ -0,0 +1,186 @@
+package godiscord
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hagesjo/webgockets"
+)
+
+// Gateway rate limit. Discord allows 120 events per 60 seconds to be sent on a connection, and closes the connection
+// with CloseEventRateLimited when it's exceeded. Commands are held back a bit before that, so that there's always
+// room left for heartbeats.
+const (
+	gatewayCommandLimit  = 110
+	gatewayCommandWindow = time.Minute
+)
+
+// errShardNotConnected is returned when sending on a shard without a connection, or when the connection is closed
+// before the payload could be sent.
+var errShardNotConnected = errors.New("shard is not connected")
+
+// gatewayQueue is the outbound queue of a gateway connection. All writes to the connection go through it, since
+// the websocket doesn't allow concurrent writes.
+//
+// Commands are sent in order, and are kept under discord's rate limit. Heartbeats skip the line and are never held
+// back, as a late heartbeat gets the connection closed. Once the connection is closed, everything still queued fails.
+type gatewayQueue struct {
+	conn gatewayConn
+
+	// The rate limit, gatewayCommandLimit commands per gatewayCommandWindow.
+	limit  int
+	window time.Duration
+
+	// The channels are unbuffered, so that a write is either taken by the writer, or fails when the queue is closed.
+	heartbeats chan *gatewayWrite
+	commands   chan *gatewayWrite
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	done      chan struct{} // Closed once run has returned, after which nothing more is written.
+
+	sent []time.Time // When the events within the rate limit window were sent, oldest first. Only used by run.
+}
+
+// gatewayConn is the connection written to by a gatewayQueue, a *webgockets.Client.
+type gatewayConn interface {
+	Write(data []byte) (int, error)
+	WriteBinary(data []byte) (int, error)
+}
+
+// gatewayWrite is a single payload to write.
+type gatewayWrite struct {
+	data   []byte
+	binary bool
+	result chan error // Buffered, so that the writer never blocks on a write that has been given up on.
+}
+
+func newGatewayQueue(wsClient *webgockets.Client) *gatewayQueue {
+	return newGatewayQueueWithLimit(wsClient, gatewayCommandLimit, gatewayCommandWindow)
+}
+
+func newGatewayQueueWithLimit(conn gatewayConn, limit int, window time.Duration) *gatewayQueue {
+	return &gatewayQueue{
+		conn:       conn,
+		limit:      limit,
+		window:     window,
+		heartbeats: make(chan *gatewayWrite),
+		commands:   make(chan *gatewayWrite),
+		closed:     make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// run writes the queued payloads until the queue is closed.
+func (q *gatewayQueue) run() {
+	defer close(q.done)
+
+	for {
+		// Heartbeats go first, even if commands are waiting.
+		select {
+		case w := <-q.heartbeats:
+			q.write(w)
+			continue
+		default:
+		}
+
+		select {
+		case <-q.closed:
+			return
+		case w := <-q.heartbeats:
+			q.write(w)
+		case w := <-q.commands:
+			if !q.waitForCommand() {
+				w.result <- errShardNotConnected
+				return
+			}
+
+			q.write(w)
+		}
+	}
+}
+
+// waitForCommand waits until the rate limit allows another command, writing any heartbeats meanwhile.
+// It returns false if the queue was closed while waiting.
+func (q *gatewayQueue) waitForCommand() bool {
+	for {
+		now := time.Now()
+		for len(q.sent) > 0 && now.Sub(q.sent[0]) >= q.window {
+			q.sent = q.sent[1:]
+		}
+
+		if len(q.sent) < q.limit {
+			return true
+		}
+
+		timer := time.NewTimer(q.sent[0].Add(q.window).Sub(now))
+		select {
+		case <-q.closed:
+			timer.Stop()
+			return false
+		case w := <-q.heartbeats:
+			timer.Stop()
+			q.write(w)
+		case <-timer.C:
+		}
+	}
+}
+
+func (q *gatewayQueue) write(w *gatewayWrite) {
+	var err error
+	if w.binary {
+		_, err = q.conn.WriteBinary(w.data)
+	} else {
+		_, err = q.conn.Write(w.data)
+	}
+
+	q.sent = append(q.sent, time.Now())
+	w.result <- err
+}
+
+// enqueue queues the write and waits until it has been written.
+// A heartbeat is written before any commands, while commands are written in order once the rate limit allows it.
+func (q *gatewayQueue) enqueue(ctx context.Context, w *gatewayWrite, heartbeat bool) error {
+	queue := q.commands
+	if heartbeat {
+		queue = q.heartbeats
+	}
+
+	select {
+	case queue <- w:
+	case <-q.closed:
+		return errShardNotConnected
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-w.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close stops the writer and fails everything still queued.
+func (q *gatewayQueue) close() {
+	q.closeOnce.Do(func() {
+		close(q.closed)
+	})
+}
+
+// wait waits for the writer to stop after close, so that the connection can be written to directly.
+// It returns false if the writer is still stuck on a write after timeout.
+func (q *gatewayQueue) wait(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-q.done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
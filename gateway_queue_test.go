@@ -0,0 +1,153 @@
+package godiscord
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingConn records the payloads written to it, and when.
+type recordingConn struct {
+	mu     sync.Mutex
+	writes []string
+	times  []time.Time
+}
+
+func (c *recordingConn) Write(data []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writes = append(c.writes, string(data))
+	c.times = append(c.times, time.Now())
+	return len(data), nil
+}
+
+func (c *recordingConn) WriteBinary(data []byte) (int, error) {
+	return c.Write(data)
+}
+
+func (c *recordingConn) written() ([]string, []time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.writes...), append([]time.Time(nil), c.times...)
+}
+
+func newTestGatewayQueue(t *testing.T, limit int, window time.Duration) (*gatewayQueue, *recordingConn) {
+	t.Helper()
+
+	conn := &recordingConn{}
+	q := newGatewayQueueWithLimit(conn, limit, window)
+	go q.run()
+	t.Cleanup(func() {
+		q.close()
+		q.wait(time.Second)
+	})
+
+	return q, conn
+}
+
+func enqueueString(q *gatewayQueue, data string, heartbeat bool) error {
+	return q.enqueue(context.Background(), &gatewayWrite{data: []byte(data), result: make(chan error, 1)}, heartbeat)
+}
+
+func TestGatewayQueueHeartbeatsSkipRateLimitedCommands(t *testing.T) {
+	const window = 300 * time.Millisecond
+	q, conn := newTestGatewayQueue(t, 2, window)
+
+	for _, command := range []string{"command 1", "command 2"} {
+		if err := enqueueString(q, command, false); err != nil {
+			t.Fatalf("failed to send %s: %v", command, err)
+		}
+	}
+
+	// The limit is reached, so the third command waits for the window to pass.
+	commandSent := make(chan error, 1)
+	go func() {
+		commandSent <- enqueueString(q, "command 3", false)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	if err := enqueueString(q, "heartbeat", true); err != nil {
+		t.Fatalf("failed to send heartbeat: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > window/2 {
+		t.Errorf("heartbeat waited %v for the rate limit", elapsed)
+	}
+
+	if err := <-commandSent; err != nil {
+		t.Fatalf("failed to send command 3: %v", err)
+	}
+
+	writes, _ := conn.written()
+	want := []string{"command 1", "command 2", "heartbeat", "command 3"}
+	if len(writes) != len(want) {
+		t.Fatalf("got writes %q, want %q", writes, want)
+	}
+
+	for i := range want {
+		if writes[i] != want[i] {
+			t.Fatalf("got writes %q, want %q", writes, want)
+		}
+	}
+}
+
+func TestGatewayQueueKeepsCommandsWithinTheWindow(t *testing.T) {
+	const (
+		limit    = 3
+		window   = 150 * time.Millisecond
+		commands = 8
+	)
+	q, conn := newTestGatewayQueue(t, limit, window)
+
+	var wg sync.WaitGroup
+	for i := 0; i < commands; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := enqueueString(q, "command", false); err != nil {
+				t.Errorf("failed to send command: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	_, times := conn.written()
+	if len(times) != commands {
+		t.Fatalf("got %d writes, want %d", len(times), commands)
+	}
+
+	// No window holds more than limit writes.
+	for i := limit; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-limit]); gap < window {
+			t.Errorf("writes %d and %d are %v apart, want at least %v", i-limit, i, gap, window)
+		}
+	}
+}
+
+func TestGatewayQueueFailsWritesOnceClosed(t *testing.T) {
+	q, _ := newTestGatewayQueue(t, 1, time.Hour)
+
+	if err := enqueueString(q, "command 1", false); err != nil {
+		t.Fatalf("failed to send command: %v", err)
+	}
+
+	sent := make(chan error, 1)
+	go func() {
+		sent <- enqueueString(q, "command 2", false)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	q.close()
+
+	if err := <-sent; err != errShardNotConnected {
+		t.Errorf("got error %v, want %v", err, errShardNotConnected)
+	}
+
+	if err := enqueueString(q, "heartbeat", true); err != errShardNotConnected {
+		t.Errorf("got error %v, want %v", err, errShardNotConnected)
+	}
+}
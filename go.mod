@@ -2,4 +2,4 @@ module github.com/hagesjo/godiscord
 
 go 1.21.3
 
-require github.com/hagesjo/webgockets v0.2.2
+require github.com/hagesjo/webgockets v0.2.3
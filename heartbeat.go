@@ -0,0 +1,128 @@
+package godiscord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// errZombieConnection is returned by the heartbeater when discord didn't acknowledge the previous heartbeat,
+// meaning that the connection is most likely dead without having been closed.
+var errZombieConnection = errors.New("heartbeat not acknowledged, connection is zombied")
+
+// heartbeater sends heartbeats at the interval given by discord, until ctx is done.
+// An error is returned if a heartbeat couldn't be sent or wasn't acknowledged in time, and the connection should be
+// considered lost.
+func (s *Shard) heartbeater(ctx context.Context) error {
+	if s.heartbeatInterval == 0 {
+		return fmt.Errorf("heartbeatInterval must be > 0")
+	}
+
+	interval := time.Duration(s.heartbeatInterval) * time.Millisecond
+
+	// The first heartbeat is sent after a random part of the interval, so that clients reconnecting at the same
+	// time don't heartbeat in lockstep.
+	timer := time.NewTimer(time.Duration(rand.Float64() * float64(interval)))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			s.mu.Lock()
+			acked := s.heartbeatAcked
+			s.mu.Unlock()
+
+			if !acked {
+				s.bot.logger.Warn("Heartbeat not acknowledged, reconnecting.", "shard", s.id)
+				return errZombieConnection
+			}
+
+			if err := s.heartbeat(ctx, true); err != nil {
+				return err
+			}
+
+			timer.Reset(interval)
+		}
+	}
+}
+
+// heartbeat sends a heartbeat with the last received sequence number.
+// Only scheduled heartbeats must be acknowledged before the next one is due: a heartbeat requested by discord is sent
+// in between, and must not make the heartbeater consider the connection zombied if its ack is late.
+func (s *Shard) heartbeat(ctx context.Context, scheduled bool) error {
+	s.mu.Lock()
+	if scheduled {
+		s.heartbeatAcked = false
+		s.lastHeartbeatSent = time.Now()
+	}
+	seq := s.lastSequence
+	s.mu.Unlock()
+
+	if err := s.enqueue(ctx, Heartbeat{
+		OpCode:       OpCodeHeartbeat,
+		LastSequence: seq,
+	}, true); err != nil {
+		return fmt.Errorf("failed to send heartbeat: %w", err)
+	}
+
+	s.bot.logger.Debug("Sent heartbeat.", "shard", s.id)
+
+	return nil
+}
+
+// heartbeatAck records the acknowledgement of the last heartbeat.
+func (s *Shard) heartbeatAck() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.heartbeatAcked = true
+	if !s.lastHeartbeatSent.IsZero() {
+		s.latency = time.Since(s.lastHeartbeatSent)
+	}
+
+	s.bot.logger.Debug("Got heartbeat ack.", "shard", s.id, "latency", s.latency)
+}
+
+func (s *Shard) sequence() *uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastSequence
+}
+
+func (s *Shard) setSequence(seq *uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSequence = seq
+}
+
+// Latency returns the round-trip time of the last acknowledged heartbeat of the shard, or 0 if none has been acknowledged yet.
+func (s *Shard) Latency() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.latency
+}
+
+// Latency returns the average heartbeat round-trip time of the shards, or 0 if no heartbeat has been acknowledged yet.
+func (b *Bot) Latency() time.Duration {
+	var total time.Duration
+	var n int
+	for _, shard := range b.shardManager.shards {
+		if latency := shard.Latency(); latency > 0 {
+			total += latency
+			n++
+		}
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	return total / time.Duration(n)
+}
@@ -21,6 +21,25 @@ const (
 
 	IntentAutoModerationConfiguration = 1 << 20
 	IntentAutoModerationExecution     = 1 << 21
+
+	IntentGuildMessagePolls  = 1 << 24
+	IntentDirectMessagePolls = 1 << 25
 )
 
-// TODO: make some sensible default intents.
+// Intent presets.
+const (
+	// IntentsPrivileged are the intents that must be enabled for the application in the developer portal,
+	// and for verified applications approved by discord.
+	IntentsPrivileged = IntentGuildMembers | IntentGuildPresences | IntentMessageContent
+
+	// IntentsAll are all intents, including the privileged ones.
+	IntentsAll = IntentGuilds | IntentGuildMembers | IntentGuildModeration | IntentGuildEmojisAndStickers |
+		IntentGuildIntegrations | IntentGuildWebhooks | IntentGuildInvites | IntentGuildVoiceStates |
+		IntentGuildPresences | IntentGuildMessages | IntentGuildMessageReactions | IntentGuildMessageTyping |
+		IntentDirectMessages | IntentDirectMessageReactions | IntentDirectMessageTyping | IntentMessageContent |
+		IntentGuildScheduledEvents | IntentAutoModerationConfiguration | IntentAutoModerationExecution |
+		IntentGuildMessagePolls | IntentDirectMessagePolls
+
+	// IntentsDefault are all intents that aren't privileged. This is what a bot identifies with unless WithIntents is used.
+	IntentsDefault = IntentsAll &^ IntentsPrivileged
+)
@@ -0,0 +1,233 @@
+package godiscord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Interaction is the message an application receives when a user uses an application command or a message component.
+type Interaction struct {
+	ID             string                 `json:"id"`                        // ID of the interaction.
+	ApplicationID  string                 `json:"application_id"`            // ID of the application this interaction is for.
+	Type           MessageInteractionType `json:"type"`                      // Type of interaction.
+	Data           json.RawMessage        `json:"data,omitempty"`            // Interaction data payload. Use CommandData() to get it unmarshaled.
+	GuildID        *string                `json:"guild_id,omitempty"`        // Guild that the interaction was sent from.
+	Channel        *Channel               `json:"channel,omitempty"`         // Channel that the interaction was sent from.
+	ChannelID      *string                `json:"channel_id,omitempty"`      // Channel that the interaction was sent from.
+	Member         *GuildMember           `json:"member,omitempty"`          // Guild member data for the invoking user, including permissions.
+	User           *User                  `json:"user,omitempty"`            // User object for the invoking user, if invoked in a DM.
+	Token          string                 `json:"token"`                     // Continuation token for responding to the interaction.
+	Version        int                    `json:"version"`                   // Read-only property, always 1.
+	Message        *Message               `json:"message,omitempty"`         // For components, the message they were attached to.
+	AppPermissions *string                `json:"app_permissions,omitempty"` // Bitwise set of permissions the app or bot has within the channel the interaction was sent from.
+	Locale         *string                `json:"locale,omitempty"`          // Selected language of the invoking user.
+	GuildLocale    *string                `json:"guild_locale,omitempty"`    // Guild's preferred locale, if invoked in a guild.
+}
+
+// Invoker returns the user that triggered the interaction, regardless if it was triggered in a guild or in a DM.
+func (i *Interaction) Invoker() *User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+
+	return i.User
+}
+
+// CommandData unmarshals the data of an APPLICATION_COMMAND or APPLICATION_COMMAND_AUTOCOMPLETE interaction.
+func (i *Interaction) CommandData() (ApplicationCommandData, error) {
+	if i.Type != MessageInteractionApplicationCommand && i.Type != MessageInteractionApplicationAutocomplete {
+		return ApplicationCommandData{}, fmt.Errorf("interaction of type %d is not an application command", i.Type)
+	}
+
+	return UnmarshalJSON[ApplicationCommandData](i.Data)
+}
+
+// ApplicationCommandData is the data sent with an application command interaction.
+type ApplicationCommandData struct {
+	ID       string                                    `json:"id"`                  // ID of the invoked command.
+	Name     string                                    `json:"name"`                // Name of the invoked command.
+	Type     ApplicationCommandType                    `json:"type"`                // Type of the invoked command.
+	Resolved *MessageResolvedData                      `json:"resolved,omitempty"`  // Converted users + roles + channels + attachments.
+	Options  []ApplicationCommandInteractionDataOption `json:"options,omitempty"`   // Params + values from the user.
+	GuildID  *string                                   `json:"guild_id,omitempty"`  // ID of the guild the command is registered to.
+	TargetID *string                                   `json:"target_id,omitempty"` // ID of the user or message targeted by a user or message command.
+}
+
+// ApplicationCommandInteractionDataOption is an option, with its value, as sent by the invoking user.
+// Options of type SUB_COMMAND and SUB_COMMAND_GROUP do not have a value, but nestles the options instead.
+type ApplicationCommandInteractionDataOption struct {
+	Name    string                                    `json:"name"`              // Name of the parameter.
+	Type    ApplicationCommandOptionType              `json:"type"`              // Value of the application command option type.
+	Value   json.RawMessage                           `json:"value,omitempty"`   // Value of the option resulting from user input. Either a string, integer, double or boolean.
+	Options []ApplicationCommandInteractionDataOption `json:"options,omitempty"` // Present if this option is a group or subcommand.
+	Focused bool                                      `json:"focused,omitempty"` // true if this option is the currently focused option for autocomplete.
+}
+
+// ApplicationCommand is the definition of an application command, as stored by discord.
+type ApplicationCommand struct {
+	ID                       string                     `json:"id,omitempty"`                         // Unique ID of command.
+	Type                     ApplicationCommandType     `json:"type,omitempty"`                       // Type of command, defaults to CHAT_INPUT.
+	ApplicationID            string                     `json:"application_id,omitempty"`             // ID of the parent application.
+	GuildID                  *string                    `json:"guild_id,omitempty"`                   // Guild ID of the command, if not global.
+	Name                     string                     `json:"name"`                                 // Name of command, 1-32 characters.
+	Description              string                     `json:"description"`                          // Description for CHAT_INPUT commands, 1-100 characters. Empty string for USER and MESSAGE commands.
+	Options                  []ApplicationCommandOption `json:"options,omitempty"`                    // Parameters for the command, max of 25. Only for CHAT_INPUT commands.
+	DefaultMemberPermissions *string                    `json:"default_member_permissions,omitempty"` // Set of permissions represented as a bit set.
+	DMPermission             *bool                      `json:"dm_permission,omitempty"`              // Indicates whether the command is available in DMs with the app, only for globally-scoped commands.
+	NSFW                     bool                       `json:"nsfw,omitempty"`                       // Indicates whether the command is age-restricted.
+	Version                  string                     `json:"version,omitempty"`                    // Autoincrementing version identifier updated during substantial record changes.
+}
+
+// ApplicationCommandType is the type of an application command.
+type ApplicationCommandType int
+
+const (
+	ApplicationCommandTypeChatInput ApplicationCommandType = 1 // Slash commands; a text-based command that shows up when a user types /.
+	ApplicationCommandTypeUser      ApplicationCommandType = 2 // A UI-based command that shows up when you right click or tap on a user.
+	ApplicationCommandTypeMessage   ApplicationCommandType = 3 // A UI-based command that shows up when you right click or tap on a message.
+)
+
+// ApplicationCommandOption is a parameter of an application command.
+// Required options must be listed before optional options.
+type ApplicationCommandOption struct {
+	Type         ApplicationCommandOptionType     `json:"type"`                    // Type of option.
+	Name         string                           `json:"name"`                    // 1-32 character name.
+	Description  string                           `json:"description"`             // 1-100 character description.
+	Required     bool                             `json:"required,omitempty"`      // Whether the parameter is required or optional, defaults to false.
+	Choices      []ApplicationCommandOptionChoice `json:"choices,omitempty"`       // Choices for STRING, INTEGER, and NUMBER types for the user to pick from, max 25.
+	Options      []ApplicationCommandOption       `json:"options,omitempty"`       // If the option is a subcommand or subcommand group type, these nested options will be the parameters.
+	ChannelTypes []ChannelType                    `json:"channel_types,omitempty"` // If the option is a channel type, the channels shown will be restricted to these types.
+	MinValue     *float64                         `json:"min_value,omitempty"`     // If the option is an INTEGER or NUMBER type, the minimum value permitted.
+	MaxValue     *float64                         `json:"max_value,omitempty"`     // If the option is an INTEGER or NUMBER type, the maximum value permitted.
+	MinLength    *int                             `json:"min_length,omitempty"`    // For option type STRING, the minimum allowed length (minimum of 0, maximum of 6000).
+	MaxLength    *int                             `json:"max_length,omitempty"`    // For option type STRING, the maximum allowed length (minimum of 1, maximum of 6000).
+	Autocomplete bool                             `json:"autocomplete,omitempty"`  // If autocomplete interactions are enabled for this STRING, INTEGER, or NUMBER type option.
+}
+
+// ApplicationCommandOptionChoice is a predefined choice for an option.
+type ApplicationCommandOptionChoice struct {
+	Name  string `json:"name"`  // 1-100 character choice name.
+	Value any    `json:"value"` // Value for the choice, up to 100 characters if string. Must be a string, an integer or a float.
+}
+
+// ApplicationCommandOptionType is the type of an application command option.
+type ApplicationCommandOptionType int
+
+const (
+	ApplicationCommandOptionTypeSubCommand      ApplicationCommandOptionType = 1
+	ApplicationCommandOptionTypeSubCommandGroup ApplicationCommandOptionType = 2
+	ApplicationCommandOptionTypeString          ApplicationCommandOptionType = 3
+	ApplicationCommandOptionTypeInteger         ApplicationCommandOptionType = 4 // Any integer between -2^53 and 2^53.
+	ApplicationCommandOptionTypeBoolean         ApplicationCommandOptionType = 5
+	ApplicationCommandOptionTypeUser            ApplicationCommandOptionType = 6
+	ApplicationCommandOptionTypeChannel         ApplicationCommandOptionType = 7 // Includes all channel types + categories.
+	ApplicationCommandOptionTypeRole            ApplicationCommandOptionType = 8
+	ApplicationCommandOptionTypeMentionable     ApplicationCommandOptionType = 9  // Includes users and roles.
+	ApplicationCommandOptionTypeNumber          ApplicationCommandOptionType = 10 // Any double between -2^53 and 2^53.
+	ApplicationCommandOptionTypeAttachment      ApplicationCommandOptionType = 11
+)
+
+// InteractionResponder responds to a single interaction.
+// The first response must be sent within 3 seconds, either as a reply or by deferring it.
+// After that, the interaction token can be used for 15 minutes to edit the response or send follow-ups.
+type InteractionResponder struct {
+	restClient  *restClient
+	interaction Interaction
+	ctx         context.Context
+}
+
+// Responder returns an InteractionResponder for the given interaction. Its requests are canceled when the fetcher's
+// context is done, see Fetcher.Context and InteractionResponder.WithContext.
+func (f *Fetcher) Responder(interaction Interaction) *InteractionResponder {
+	return &InteractionResponder{
+		restClient:  f.restClient,
+		interaction: interaction,
+		ctx:         f.Context(),
+	}
+}
+
+// WithContext returns a copy of the responder whose requests are canceled when ctx is done.
+func (r *InteractionResponder) WithContext(ctx context.Context) *InteractionResponder {
+	r2 := *r
+	r2.ctx = ctx
+	return &r2
+}
+
+// Respond sends a raw interaction response.
+func (r *InteractionResponder) Respond(resp InteractionResponse) error {
+	return r.restClient.CreateInteractionResponse(r.ctx, r.interaction.ID, r.interaction.Token, resp)
+}
+
+// Reply responds to the interaction with a message.
+func (r *InteractionResponder) Reply(msg InteractionMessage) error {
+	return r.Respond(InteractionResponse{
+		Type: InteractionCallbackChannelMessageWithSource,
+		Data: msg,
+	})
+}
+
+// ReplyContent responds to the interaction with a text message.
+func (r *InteractionResponder) ReplyContent(content string) error {
+	return r.Reply(InteractionMessage{Content: content})
+}
+
+// ReplyEphemeral responds to the interaction with a text message only visible to the invoking user.
+func (r *InteractionResponder) ReplyEphemeral(content string) error {
+	return r.Reply(InteractionMessage{Content: content, Flags: MessageFlagEphemeral})
+}
+
+// Defer acknowledges the interaction and shows the user a "thinking" state.
+// The actual response is then sent with EditOriginal.
+func (r *InteractionResponder) Defer(ephemeral bool) error {
+	resp := InteractionResponse{Type: InteractionCallbackDeferredChannelMessageWithSource}
+	if ephemeral {
+		resp.Data = InteractionMessage{Flags: MessageFlagEphemeral}
+	}
+
+	return r.Respond(resp)
+}
+
+// UpdateMessage responds to a component interaction by editing the message the component was attached to.
+func (r *InteractionResponder) UpdateMessage(msg InteractionMessage) error {
+	return r.Respond(InteractionResponse{
+		Type: InteractionCallbackUpdateMessage,
+		Data: msg,
+	})
+}
+
+// DeferUpdate acknowledges a component interaction without showing a loading state.
+// The message the component was attached to can then be edited later with EditOriginal.
+func (r *InteractionResponder) DeferUpdate() error {
+	return r.Respond(InteractionResponse{Type: InteractionCallbackDeferredUpdateMessage})
+}
+
+// GetOriginal returns the initial response to the interaction.
+func (r *InteractionResponder) GetOriginal() (*Message, error) {
+	return r.restClient.GetOriginalInteractionResponse(r.ctx, r.interaction.ApplicationID, r.interaction.Token)
+}
+
+// EditOriginal edits the initial response to the interaction, or sends it if the interaction was deferred.
+func (r *InteractionResponder) EditOriginal(msg InteractionMessage) (*Message, error) {
+	return r.restClient.EditOriginalInteractionResponse(r.ctx, r.interaction.ApplicationID, r.interaction.Token, msg)
+}
+
+// DeleteOriginal deletes the initial response to the interaction.
+func (r *InteractionResponder) DeleteOriginal() error {
+	return r.restClient.DeleteOriginalInteractionResponse(r.ctx, r.interaction.ApplicationID, r.interaction.Token)
+}
+
+// Followup sends a follow-up message to the interaction.
+func (r *InteractionResponder) Followup(msg InteractionMessage) (*Message, error) {
+	return r.restClient.CreateFollowupMessage(r.ctx, r.interaction.ApplicationID, r.interaction.Token, msg)
+}
+
+// EditFollowup edits a follow-up message previously sent to the interaction.
+func (r *InteractionResponder) EditFollowup(messageID string, msg InteractionMessage) (*Message, error) {
+	return r.restClient.EditFollowupMessage(r.ctx, r.interaction.ApplicationID, r.interaction.Token, messageID, msg)
+}
+
+// DeleteFollowup deletes a follow-up message previously sent to the interaction.
+func (r *InteractionResponder) DeleteFollowup(messageID string) error {
+	return r.restClient.DeleteFollowupMessage(r.ctx, r.interaction.ApplicationID, r.interaction.Token, messageID)
+}
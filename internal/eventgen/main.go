@@ -0,0 +1,157 @@
+// Command eventgen generates the boilerplate of the dispatch events.
+//
+// It reads the event types marked with a //godiscord:event directive naming the event, e.g.
+//
+//	// MessageCreate is sent when a message is created.
+//	//godiscord:event MESSAGE_CREATE
+//	type MessageCreate struct {
+//
+// and generates the EventName methods making them a DispatchEvent, the decoders of the events by name, and the
+// type switch used to register listeners given as any.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+)
+
+const directive = "//godiscord:event "
+
+type event struct {
+	Type string // Name of the Go type.
+	Name string // Name of the dispatch event.
+}
+
+func main() {
+	in := flag.String("in", "events_receive.go", "file with the event types")
+	out := flag.String("out", "events_gen.go", "file to generate")
+	flag.Parse()
+
+	events, err := parseEvents(*in)
+	if err != nil {
+		log.Fatalf("Failed to parse events: %v", err)
+	}
+
+	src, err := generate(events)
+	if err != nil {
+		log.Fatalf("Failed to generate events: %v", err)
+	}
+
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+}
+
+// parseEvents returns the event types of a file, in the order they're declared.
+func parseEvents(path string) ([]event, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	var events []event
+	seen := make(map[string]string)
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+
+			doc := typeSpec.Doc
+			if doc == nil {
+				doc = genDecl.Doc
+			}
+
+			name := eventName(doc)
+			if name == "" {
+				continue
+			}
+
+			if other, ok := seen[name]; ok {
+				return nil, fmt.Errorf("event %s is declared by both %s and %s", name, other, typeSpec.Name.Name)
+			}
+			seen[name] = typeSpec.Name.Name
+
+			events = append(events, event{
+				Type: typeSpec.Name.Name,
+				Name: name,
+			})
+		}
+	}
+
+	return events, nil
+}
+
+// eventName returns the event named by the directive of a doc comment, if any.
+func eventName(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+
+	for _, comment := range doc.List {
+		if name, ok := strings.CutPrefix(comment.Text, directive); ok {
+			return strings.TrimSpace(name)
+		}
+	}
+
+	return ""
+}
+
+var tmpl = template.Must(template.New("events").Parse(`// Code generated by eventgen. DO NOT EDIT.
+
+package godiscord
+
+import (
+	"encoding/json"
+	"fmt"
+)
+{{range .}}
+func ({{.Type}}) EventName() string {
+	return "{{.Name}}"
+}
+{{end}}
+// eventDecoders decodes the dispatch events, by event name.
+var eventDecoders = map[string]func(json.RawMessage) (DispatchEvent, error){
+{{- range .}}
+	"{{.Name}}": decodeEvent[{{.Type}}],
+{{- end}}
+}
+
+// eventHandlerFromInterface returns the handler of a listener in the form of func(*Fetcher, <DispatchEvent>) error.
+func eventHandlerFromInterface(iface any) (eventHandler, error) {
+	switch v := iface.(type) {
+{{- range .}}
+	case func(*Fetcher, {{.Type}}) error:
+		return listenerFunc[{{.Type}}](v), nil
+{{- end}}
+	default:
+		return nil, fmt.Errorf("unknown event listener %T", iface)
+	}
+}
+`))
+
+func generate(events []event) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, events); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	return src, nil
+}
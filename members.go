@@ -0,0 +1,124 @@
+package godiscord
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// maxRequestMembersUserIDs is how many members can be requested by id at once.
+const maxRequestMembersUserIDs = 100
+
+// memberRequest is a pending request of guild members, collecting the GUILD_MEMBERS_CHUNK events answering it.
+type memberRequest struct {
+	members  []GuildMember
+	notFound []string
+	received int           // Number of chunks received.
+	done     chan struct{} // Closed once all chunks have been received.
+}
+
+// RequestMembers requests members of the guild over the gateway, which is needed to get all members of large guilds,
+// as GUILD_CREATE only includes some of them. The returned members are cached as well.
+//
+// Members are either looked up by userIDs (at most 100), or by a username prefix with query. An empty query with
+// limit 0 returns all members, which needs IntentGuildMembers. presences includes the members' presences, which
+// needs IntentGuildPresences. The ids in userIDs that aren't members are returned as notFound.
+//
+// The members arrive as events, which the shard matches before passing them on to the handlers, so it can be called
+// from an event listener or command handler.
+func (f *Fetcher) RequestMembers(ctx context.Context, query string, userIDs []string, limit int, presences bool) (members []GuildMember, notFound []string, err error) {
+	if f.guildID == "" {
+		return nil, nil, errNoGuild
+	}
+
+	if len(userIDs) > maxRequestMembersUserIDs {
+		return nil, nil, fmt.Errorf("at most %d user ids can be requested at once, got %d", maxRequestMembersUserIDs, len(userIDs))
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload := RequestGuildMembersPayload{
+		GuildID:   f.guildID,
+		Limit:     limit,
+		Presences: presences,
+		UserIDs:   userIDs,
+		Nonce:     nonce,
+	}
+	if len(userIDs) == 0 {
+		payload.Query = &query
+	}
+
+	request := &memberRequest{
+		done: make(chan struct{}),
+	}
+
+	s := f.shard
+	s.memberRequestsMu.Lock()
+	s.memberRequests[nonce] = request
+	s.memberRequestsMu.Unlock()
+
+	defer func() {
+		s.memberRequestsMu.Lock()
+		delete(s.memberRequests, nonce)
+		s.memberRequestsMu.Unlock()
+	}()
+
+	if err := s.send(ctx, RequestGuildMembers{
+		OpCode:  OpCodeRequestGuildMembers,
+		Payload: payload,
+	}); err != nil {
+		return nil, nil, fmt.Errorf("failed to request guild members: %w", err)
+	}
+
+	select {
+	case <-request.done:
+	case <-ctx.Done():
+		return nil, nil, fmt.Errorf("failed to receive guild members: %w", ctx.Err())
+	}
+
+	s.memberRequestsMu.Lock()
+	defer s.memberRequestsMu.Unlock()
+
+	return request.members, request.notFound, nil
+}
+
+// memberChunkReceived adds a chunk to the request it answers, if any.
+func (s *Shard) memberChunkReceived(chunk GuildMembersChunk) {
+	if chunk.Nonce == "" {
+		return
+	}
+
+	s.memberRequestsMu.Lock()
+	defer s.memberRequestsMu.Unlock()
+
+	request, ok := s.memberRequests[chunk.Nonce]
+	if !ok {
+		return
+	}
+
+	request.members = append(request.members, chunk.Members...)
+	request.notFound = append(request.notFound, chunk.NotFound...)
+	request.received++
+
+	if request.received >= chunk.ChunkCount {
+		select {
+		case <-request.done:
+		default:
+			close(request.done)
+		}
+	}
+}
+
+// newNonce returns a random nonce, within the 32 characters discord allows.
+func newNonce() (string, error) {
+	bs := make([]byte, 16)
+	if _, err := rand.Read(bs); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return hex.EncodeToString(bs), nil
+}
@@ -0,0 +1,146 @@
+package godiscord
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ModalFunc is called when a user submits a modal whose custom_id matches a registered pattern.
+type ModalFunc func(*Fetcher, ModalSubmit) error
+
+// Modal is a popup form shown to a user in response to an interaction.
+// Build one with NewModal and show it with InteractionResponder.ShowModal.
+type Modal struct {
+	CustomID   string              `json:"custom_id"`  // Developer-defined identifier for the modal, max 100 characters.
+	Title      string              `json:"title"`      // Title of the popup modal, max 45 characters.
+	Components []MessageActionType `json:"components"` // Between 1 and 5 action rows, each containing a single text input.
+
+	err error
+}
+
+// NewModal creates an empty modal.
+func NewModal(customID, title string) *Modal {
+	return &Modal{
+		CustomID: customID,
+		Title:    title,
+	}
+}
+
+// AddTextInput adds a text input to the modal, in an action row of its own.
+func (m *Modal) AddTextInput(input TextInput) *Modal {
+	input.Type = ComponentTypeTextInput
+
+	bs, err := json.Marshal(input)
+	if err != nil {
+		m.err = fmt.Errorf("failed to marshal text input %q: %w", input.CustomID, err)
+		return m
+	}
+
+	m.Components = append(m.Components, MessageActionType{
+		Type:       ComponentTypeActionRow,
+		Components: []json.RawMessage{bs},
+	})
+
+	return m
+}
+
+func (m *Modal) validate() error {
+	if m.err != nil {
+		return m.err
+	}
+
+	if m.CustomID == "" || len(m.CustomID) > 100 {
+		return fmt.Errorf("custom_id must be between 1 and 100 characters")
+	}
+
+	if l := len([]rune(m.Title)); l < 1 || l > 45 {
+		return fmt.Errorf("title must be between 1 and 45 characters")
+	}
+
+	if len(m.Components) < 1 || len(m.Components) > 5 {
+		return fmt.Errorf("a modal must have between 1 and 5 text inputs")
+	}
+
+	return nil
+}
+
+// ShowModal responds to the interaction by showing a modal to the user.
+// Modals can't be shown in response to a modal submission, nor after deferring.
+func (r *InteractionResponder) ShowModal(modal *Modal) error {
+	if err := modal.validate(); err != nil {
+		return fmt.Errorf("invalid modal: %w", err)
+	}
+
+	return r.Respond(InteractionResponse{
+		Type: InteractionCallbackModal,
+		Data: modal,
+	})
+}
+
+// ModalSubmitData is the data sent with a modal submit interaction.
+type ModalSubmitData struct {
+	CustomID   string              `json:"custom_id"`  // Developer-defined identifier of the modal.
+	Components []MessageActionType `json:"components"` // The values submitted by the user, as action rows of text inputs.
+}
+
+// ModalSubmit is a modal submit interaction, routed by the custom_id of the modal.
+type ModalSubmit struct {
+	Interaction
+
+	CustomID string // Custom ID of the submitted modal.
+
+	// Params holds the values of the {param} placeholders in the pattern the custom_id matched.
+	Params map[string]string
+
+	// Values holds the submitted text input values keyed by the custom_id of each text input.
+	Values map[string]string
+}
+
+// Value returns the submitted value of the text input with the given custom_id.
+func (m ModalSubmit) Value(customID string) string {
+	return m.Values[customID]
+}
+
+// RegisterModalHandler registers a handler for modal submissions.
+// customIDPattern follows the same rules as in RegisterComponentHandler.
+func (b *Bot) RegisterModalHandler(customIDPattern string, handler ModalFunc) error {
+	return b.modalRoutes.add(customIDPattern, handler)
+}
+
+func (b *Bot) handleModalSubmit(fetcher *Fetcher, interaction Interaction) error {
+	data, err := UnmarshalJSON[ModalSubmitData](interaction.Data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal modal submit data: %w", err)
+	}
+
+	values := make(map[string]string)
+	for _, row := range data.Components {
+		for _, component := range row.Components {
+			input, err := UnmarshalJSON[TextInput](component)
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal text input: %w", err)
+			}
+
+			if input.Value != nil {
+				values[input.CustomID] = *input.Value
+			}
+		}
+	}
+
+	handler, params, ok := b.modalRoutes.match(data.CustomID)
+	if !ok {
+		b.logger.Warn("Received modal submission without a handler.", "custom_id", data.CustomID)
+		return nil
+	}
+
+	if err := handler(fetcher, ModalSubmit{
+		Interaction: interaction,
+		CustomID:    data.CustomID,
+		Params:      params,
+		Values:      values,
+	}); err != nil {
+		return fmt.Errorf("modal handler of %s failed: %w", data.CustomID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package godiscord
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Option configures a Bot, see NewBot.
+type Option func(*Bot)
+
+// Limits of the large threshold, see WithLargeThreshold.
+const (
+	minLargeThreshold = 50
+	maxLargeThreshold = 250
+)
+
+// Encoding is the encoding of the payloads sent over the gateway.
+type Encoding string
+
+// Gateway encodings.
+const (
+	EncodingJSON Encoding = "json"
+	// EncodingETF is the Erlang External Term Format, which is considerably more compact than JSON.
+	EncodingETF Encoding = "etf"
+)
+
+// WithCompression enables zlib-stream transport compression of the gateway connections.
+// Large payloads like GUILD_CREATE compress very well, at the cost of some CPU time.
+func WithCompression() Option {
+	return func(b *Bot) {
+		b.compression = true
+	}
+}
+
+// WithEncoding sets the encoding of the gateway payloads. Defaults to EncodingJSON.
+func WithEncoding(encoding Encoding) Option {
+	return func(b *Bot) {
+		b.encoding = encoding
+	}
+}
+
+// WithPrefix sets the prefix of text commands. Defaults to "!".
+func WithPrefix(prefix string) Option {
+	return func(b *Bot) {
+		b.prefix = prefix
+	}
+}
+
+// WithIntents sets the gateway intents the bot identifies with, e.g. IntentsDefault | IntentMessageContent.
+// Defaults to IntentsDefault. Privileged intents must be enabled for the application in the developer portal,
+// or discord closes the connection with CloseEventDisallowedIntents.
+//
+// Text commands need IntentMessageContent to see the content of messages outside of DMs and mentions.
+func WithIntents(intents int) Option {
+	return func(b *Bot) {
+		b.intents = intents
+	}
+}
+
+// WithPresence sets the presence the bot has when it connects. By default no presence is sent.
+func WithPresence(presence *Presence) Option {
+	return func(b *Bot) {
+		b.presence = presence
+	}
+}
+
+// WithLargeThreshold sets the number of members (50-250) at which discord stops sending offline members of a guild
+// in GUILD_CREATE. Discord defaults to 50. NewBot fails if the threshold is out of range.
+func WithLargeThreshold(threshold int) Option {
+	return func(b *Bot) {
+		if threshold < minLargeThreshold || threshold > maxLargeThreshold {
+			b.optionErrs = append(b.optionErrs, fmt.Errorf("invalid large threshold %d, must be %d-%d", threshold, minLargeThreshold, maxLargeThreshold))
+			return
+		}
+
+		b.largeThreshold = threshold
+	}
+}
+
+// WithShards sets the total number of shards, rather than using the number recommended by discord.
+// If ids are given, only those shards are run, which is used to spread the shards over several processes.
+func WithShards(count int, ids ...int) Option {
+	return func(b *Bot) {
+		b.shardCount = count
+		b.shardIDs = ids
+	}
+}
+
+// WithHTTPClient sets the http client used for REST calls. The client is copied, with its transport wrapped
+// to add the authorization. NewBot fails if the client is nil.
+func WithHTTPClient(client *http.Client) Option {
+	return func(b *Bot) {
+		if client == nil {
+			b.optionErrs = append(b.optionErrs, errors.New("http client must not be nil"))
+			return
+		}
+
+		b.httpClient = client
+	}
+}
+
+// WithRESTBaseURL sets the base url of the REST api, e.g. for a proxy. Defaults to https://discord.com.
+func WithRESTBaseURL(baseURL string) Option {
+	return func(b *Bot) {
+		b.restBaseURL = baseURL
+	}
+}
+
+// WithGatewayURL sets the url of the gateway, rather than using the one discord returns.
+func WithGatewayURL(gatewayURL string) Option {
+	return func(b *Bot) {
+		b.gatewayURL = gatewayURL
+	}
+}
+
+// WithLogger sets the logger. Defaults to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(b *Bot) {
+		b.logger = logger
+	}
+}
+
+// WithShutdownTimeout sets how long a shutdown waits for a running handler to finish. Defaults to 10 seconds.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(b *Bot) {
+		b.shutdownTimeout = timeout
+	}
+}
+
+// WithStateCache sets the cache of the state received through the gateway. Defaults to a MemoryStateCache caching
+// everything, use e.g. NewMemoryStateCache(CacheAll&^CacheMembers, 0) to not cache members.
+func WithStateCache(cache StateCache) Option {
+	return func(b *Bot) {
+		b.cache = cache
+	}
+}
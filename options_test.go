@@ -0,0 +1,25 @@
+package godiscord
+
+import "testing"
+
+func TestNewBotRejectsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"nil http client", WithHTTPClient(nil)},
+		{"large threshold too small", WithLargeThreshold(49)},
+		{"large threshold too large", WithLargeThreshold(251)},
+		{"unknown encoding", WithEncoding("xml")},
+		{"shard id out of range", WithShards(2, 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The options are checked before any request is made.
+			if _, err := NewBot("token", tt.opt); err == nil {
+				t.Errorf("got no error")
+			}
+		})
+	}
+}
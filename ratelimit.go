@@ -0,0 +1,259 @@
+package godiscord
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRateLimitRetries is how many times a request is retried after being rate limited, before giving up.
+const maxRateLimitRetries = 5
+
+// bucketSweepInterval is how often buckets that are no longer used are removed. Buckets are kept per major parameter,
+// and webhook and interaction tokens are majors as well, so most buckets are only used for a short while.
+const bucketSweepInterval = time.Minute
+
+var snowflakeRe = regexp.MustCompile(`^[0-9]{15,21}$`)
+
+// rateLimiter keeps track of discord's per-route rate limit buckets and the global rate limit.
+// Requests sharing a bucket are queued and sent one at a time, waiting for the bucket to reset when it's exhausted.
+//
+// Discord doesn't tell which bucket a route belongs to until a response has been received, so a route
+// starts out in a bucket of its own, keyed by the route and its major parameters. Once the X-RateLimit-Bucket
+// header has been seen, all routes reporting the same bucket share it (per major parameter).
+type rateLimiter struct {
+	mu     sync.Mutex
+	logger *slog.Logger
+
+	// routes maps a route to the bucket hash discord reported for it.
+	routes  map[string]string
+	buckets map[string]*bucket
+
+	// globalReset is when the global rate limit is lifted, zero if not globally rate limited.
+	globalReset time.Time
+
+	lastSweep time.Time // When the unused buckets were last removed.
+}
+
+// bucket is a single rate limit bucket.
+type bucket struct {
+	// lock is held by the request currently using the bucket. It's a channel rather than a mutex so that waiting can be abandoned.
+	lock chan struct{}
+
+	remaining int       // Requests left until the reset, -1 if unknown.
+	reset     time.Time // When the bucket resets.
+
+	users int // Requests that have gotten the bucket and not yet released it, guarded by rateLimiter.mu.
+}
+
+func newRateLimiter(logger *slog.Logger) *rateLimiter {
+	return &rateLimiter{
+		logger:  logger,
+		routes:  make(map[string]string),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// bucket returns the bucket used for the route, creating it if needed.
+// The bucket must then be acquired, which keeps it from being removed until it's released.
+func (r *rateLimiter) bucket(route, major string) *bucket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := time.Now(); now.Sub(r.lastSweep) >= bucketSweepInterval {
+		r.sweep(now)
+	}
+
+	key := route + "|" + major
+	if hash, ok := r.routes[route]; ok {
+		key = hash + "|" + major
+	}
+
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &bucket{
+			lock:      make(chan struct{}, 1),
+			remaining: -1,
+		}
+		r.buckets[key] = b
+	}
+
+	b.users++
+
+	return b
+}
+
+// sweep removes the buckets that aren't used by any request and have reset, as they no longer limit anything.
+// Must be called with mu held.
+func (r *rateLimiter) sweep(now time.Time) {
+	r.lastSweep = now
+
+	for key, b := range r.buckets {
+		if b.users == 0 && !now.Before(b.reset) {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// acquire waits for the bucket to be available and to have requests left, as well as for any global rate limit to be lifted.
+// The bucket must be released once the response has been handled, unless an error is returned.
+func (r *rateLimiter) acquire(ctx context.Context, b *bucket) error {
+	select {
+	case b.lock <- struct{}{}:
+	case <-ctx.Done():
+		r.unuse(b)
+		return ctx.Err()
+	}
+
+	if b.remaining == 0 {
+		if wait := time.Until(b.reset); wait > 0 {
+			r.logger.Debug("Waiting for rate limit bucket to reset.", "wait", wait)
+			if err := sleepContext(ctx, wait); err != nil {
+				r.release(b)
+				return err
+			}
+		}
+	}
+
+	r.mu.Lock()
+	globalReset := r.globalReset
+	r.mu.Unlock()
+
+	if wait := time.Until(globalReset); wait > 0 {
+		r.logger.Debug("Waiting for global rate limit to reset.", "wait", wait)
+		if err := sleepContext(ctx, wait); err != nil {
+			r.release(b)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *rateLimiter) release(b *bucket) {
+	<-b.lock
+	r.unuse(b)
+}
+
+// unuse marks that a request is done with the bucket, so that it can be removed once it has reset.
+func (r *rateLimiter) unuse(b *bucket) {
+	r.mu.Lock()
+	b.users--
+	r.mu.Unlock()
+}
+
+// update updates the bucket and the route to bucket mapping from the rate limit headers of a response.
+// Must be called while holding the bucket.
+func (r *rateLimiter) update(b *bucket, route, major string, header http.Header) {
+	if remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		b.remaining = remaining
+	}
+
+	if resetAfter, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64); err == nil {
+		b.reset = time.Now().Add(secondsToDuration(resetAfter))
+	}
+
+	hash := header.Get("X-RateLimit-Bucket")
+	if hash == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.routes[route] == hash {
+		return
+	}
+
+	// First time this route reports its bucket, share the bucket with other routes reporting the same hash.
+	r.routes[route] = hash
+	key := hash + "|" + major
+	if _, ok := r.buckets[key]; !ok {
+		r.buckets[key] = b
+	}
+}
+
+// rateLimited records a 429 response, so that the request and any following request wait for retryAfter.
+// Must be called while holding the bucket.
+func (r *rateLimiter) rateLimited(b *bucket, retryAfter time.Duration, global bool) {
+	reset := time.Now().Add(retryAfter)
+	if global {
+		r.mu.Lock()
+		r.globalReset = reset
+		r.mu.Unlock()
+		return
+	}
+
+	b.remaining = 0
+	b.reset = reset
+}
+
+// rateLimitResponse is the body of a 429 response.
+type rateLimitResponse struct {
+	Message    string  `json:"message"`     // A message saying you are being rate limited.
+	RetryAfter float64 `json:"retry_after"` // The number of seconds to wait before submitting another request.
+	Global     bool    `json:"global"`      // A value indicating if you are being globally rate limited or not.
+	Code       int     `json:"code"`        // An error code for some limits.
+}
+
+// rateLimitRoute returns the route of a request path and its major parameters.
+// Snowflakes are replaced by placeholders, except for the major parameters (channel, guild and webhook ids),
+// since discord keeps separate limits per major parameter.
+//
+// The interaction id is used as a major parameter as well. Every interaction is responded to once, so the
+// responses must not wait for each other, or they'd risk missing the deadline of the response.
+func rateLimitRoute(path string) (route, major string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var majors []string
+	for i, segment := range segments {
+		var prev string
+		if i > 0 {
+			prev = segments[i-1]
+		}
+
+		switch {
+		case i == 1 && (prev == "channels" || prev == "guilds" || prev == "webhooks"):
+			majors = append(majors, segment)
+			segments[i] = "{" + strings.TrimSuffix(prev, "s") + "_id}"
+		case i == 2 && segments[0] == "webhooks":
+			// Webhook token.
+			majors = append(majors, segment)
+			segments[i] = "{token}"
+		case i == 1 && prev == "interactions":
+			majors = append(majors, segment)
+			segments[i] = "{interaction_id}"
+		case i == 2 && segments[0] == "interactions":
+			// Interaction token.
+			segments[i] = "{token}"
+		case prev == "reactions":
+			segments[i] = "{emoji}"
+		case snowflakeRe.MatchString(segment):
+			segments[i] = "{id}"
+		}
+	}
+
+	return "/" + strings.Join(segments, "/"), strings.Join(majors, "/")
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// sleepContext sleeps for d, or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,258 @@
+package godiscord
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestRestClient(t *testing.T, handler http.HandlerFunc) *restClient {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := newRestClient(srv.Client(), srv.URL, "token", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to create rest client: %v", err)
+	}
+
+	return c
+}
+
+func TestRateLimitRoute(t *testing.T) {
+	tests := []struct {
+		path  string
+		route string
+		major string
+	}{
+		{"/channels/123456789012345678/messages/223456789012345678", "/channels/{channel_id}/messages/{id}", "123456789012345678"},
+		{"/guilds/123456789012345678/members/223456789012345678", "/guilds/{guild_id}/members/{id}", "123456789012345678"},
+		{"/channels/123456789012345678/messages/223456789012345678/reactions/%F0%9F%91%8D/@me", "/channels/{channel_id}/messages/{id}/reactions/{emoji}/@me", "123456789012345678"},
+		{"/webhooks/123456789012345678/tok/messages/@original", "/webhooks/{webhook_id}/{token}/messages/@original", "123456789012345678/tok"},
+		{"/interactions/123456789012345678/tok/callback", "/interactions/{interaction_id}/{token}/callback", "123456789012345678"},
+		{"/gateway/bot", "/gateway/bot", ""},
+	}
+
+	for _, tt := range tests {
+		route, major := rateLimitRoute(tt.path)
+		if route != tt.route || major != tt.major {
+			t.Errorf("rateLimitRoute(%q) = %q, %q, want %q, %q", tt.path, route, major, tt.route, tt.major)
+		}
+	}
+}
+
+func TestRestClientRetriesAfter429(t *testing.T) {
+	const retryAfter = 100 * time.Millisecond
+
+	var calls atomic.Int32
+	c := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"message": "You are being rate limited.", "retry_after": 0.1, "global": false}`)
+			return
+		}
+
+		io.WriteString(w, `{}`)
+	})
+
+	start := time.Now()
+	if err := c.get(context.Background(), "/channels/123456789012345678", nil); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+
+	if elapsed := time.Since(start); elapsed < retryAfter {
+		t.Errorf("retried after %v, want at least %v", elapsed, retryAfter)
+	}
+}
+
+func TestRestClientGivesUpAfterRetries(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+		io.WriteString(w, `{"message": "You are being rate limited.", "retry_after": 0.001, "global": false, "code": 0}`)
+	})
+
+	err := c.get(context.Background(), "/channels/123456789012345678", nil)
+	if !IsRateLimited(err) {
+		t.Fatalf("got error %v, want a rate limit error", err)
+	}
+
+	if n := calls.Load(); n != maxRateLimitRetries+1 {
+		t.Errorf("got %d requests, want %d", n, maxRateLimitRetries+1)
+	}
+}
+
+func TestRestClientSharesRemappedBucket(t *testing.T) {
+	const resetAfter = 150 * time.Millisecond
+
+	c := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Both routes report the same bucket, which is exhausted by the first request.
+		w.Header().Set("X-RateLimit-Bucket", "shared")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset-After", "0.15")
+		io.WriteString(w, `{}`)
+	})
+
+	ctx := context.Background()
+	start := time.Now()
+	if err := c.get(ctx, "/channels/123456789012345678/pins", nil); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	// The first request of the other route doesn't know its bucket yet.
+	if err := c.get(ctx, "/channels/123456789012345678/webhooks", nil); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	route, major := rateLimitRoute("/channels/123456789012345678/pins")
+	pins := c.rateLimiter.bucket("GET "+route, major)
+	c.rateLimiter.unuse(pins)
+
+	route, major = rateLimitRoute("/channels/123456789012345678/webhooks")
+	webhooks := c.rateLimiter.bucket("GET "+route, major)
+	c.rateLimiter.unuse(webhooks)
+
+	if pins != webhooks {
+		t.Fatalf("routes reporting the same bucket don't share it")
+	}
+
+	// Now it does, and waits for the bucket exhausted by the first route.
+	if err := c.get(ctx, "/channels/123456789012345678/webhooks", nil); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < resetAfter {
+		t.Errorf("request sent after %v, want it to wait for the shared bucket to reset after %v", elapsed, resetAfter)
+	}
+
+	// Other major parameters have buckets of their own.
+	route, major = rateLimitRoute("/channels/999999999999999999/pins")
+	other := c.rateLimiter.bucket("GET "+route, major)
+	c.rateLimiter.unuse(other)
+
+	if other == pins {
+		t.Errorf("bucket is shared between major parameters")
+	}
+}
+
+func TestRestClientInteractionCallbacksDontWaitForEachOther(t *testing.T) {
+	release := make(chan struct{})
+	var inFlight, maxInFlight atomic.Int32
+	c := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"123456789012345678", "223456789012345678"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+
+			if err := c.CreateInteractionResponse(context.Background(), id, "token-"+id, InteractionResponse{}); err != nil {
+				t.Errorf("request failed: %v", err)
+			}
+		}(id)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for maxInFlight.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := maxInFlight.Load(); n != 2 {
+		t.Errorf("got %d callbacks in flight at once, want 2", n)
+	}
+}
+
+func TestRateLimiterSweepsUnusedBuckets(t *testing.T) {
+	r := newRateLimiter(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	idle := r.bucket("POST /webhooks/{webhook_id}/{token}", "1/a")
+	r.unuse(idle)
+
+	limited := r.bucket("POST /webhooks/{webhook_id}/{token}", "1/b")
+	limited.reset = time.Now().Add(time.Hour)
+	r.unuse(limited)
+
+	inUse := r.bucket("POST /webhooks/{webhook_id}/{token}", "1/c")
+
+	r.mu.Lock()
+	r.sweep(time.Now())
+	n := len(r.buckets)
+	r.mu.Unlock()
+
+	if n != 2 {
+		t.Errorf("got %d buckets after sweeping, want the rate limited and the used one", n)
+	}
+
+	if b := r.bucket("POST /webhooks/{webhook_id}/{token}", "1/c"); b != inUse {
+		t.Errorf("bucket in use was removed")
+	}
+}
+
+func TestRestClientDoesntLogTokens(t *testing.T) {
+	const token = "aW50ZXJhY3Rpb246MTA1MDEyNDMxMjQ0MDg3NzIwNw"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		io.WriteString(w, `{"message": "You are being rate limited.", "retry_after": 0.001, "global": false}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	var logs bytes.Buffer
+	c, err := newRestClient(srv.Client(), srv.URL, "token", slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	if err != nil {
+		t.Fatalf("failed to create rest client: %v", err)
+	}
+
+	err = c.CreateInteractionResponse(context.Background(), "123456789012345678", token, InteractionResponse{})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got error %v, want an api error", err)
+	}
+
+	if want := "/interactions/123456789012345678/{token}/callback"; apiErr.Path != want {
+		t.Errorf("got path %q, want %q", apiErr.Path, want)
+	}
+
+	if strings.Contains(err.Error(), token) || strings.Contains(logs.String(), token) {
+		t.Errorf("token was logged:\n%s\n%s", err, logs.String())
+	}
+
+	// Errors of the http client contain the url of the request.
+	srv.Close()
+	err = c.CreateInteractionResponse(context.Background(), "223456789012345678", token, InteractionResponse{})
+	if err == nil || strings.Contains(err.Error(), token) {
+		t.Errorf("got error %v, want one without the token", err)
+	}
+}
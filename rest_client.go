@@ -2,16 +2,21 @@ package godiscord
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 const (
-	restBaseURL = "https://discord.com"
-	apiVersion  = 10
+	defaultRESTBaseURL = "https://discord.com"
+	apiVersion         = 10
 )
 
 type transport struct {
@@ -26,118 +31,196 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 type RestClient interface{}
 
-func newRestClient(client *http.Client, authToken string) (*restClient, error) {
-	baseURL, err := url.JoinPath(restBaseURL, fmt.Sprintf("api/v%d", apiVersion))
+// newRestClient creates a rest client for the API at baseURL, using client to make the requests.
+// The client is copied, so that adding the authorization doesn't affect other users of it.
+func newRestClient(client *http.Client, baseURL, authToken string, logger *slog.Logger) (*restClient, error) {
+	apiURL, err := url.JoinPath(baseURL, fmt.Sprintf("api/v%d", apiVersion))
 	if err != nil {
 		return nil, fmt.Errorf("failed to build base url: %w", err)
 	}
 
-	restClient := &restClient{
-		httpClient: client,
-		baseURL:    baseURL,
-		authToken:  authToken,
+	underlyingTransport := client.Transport
+	if underlyingTransport == nil {
+		underlyingTransport = http.DefaultTransport
 	}
 
-	restClient.httpClient.Transport = &transport{
-		underlyingTransport: http.DefaultTransport,
+	httpClient := *client
+	httpClient.Transport = &transport{
+		underlyingTransport: underlyingTransport,
 		authToken:           authToken,
 	}
 
+	restClient := &restClient{
+		httpClient:  &httpClient,
+		baseURL:     apiURL,
+		authToken:   authToken,
+		logger:      logger,
+		rateLimiter: newRateLimiter(logger),
+	}
+
 	return restClient, nil
 }
 
 type restClient struct {
-	httpClient *http.Client
-	baseURL    string
-	authToken  string
+	httpClient  *http.Client
+	baseURL     string
+	authToken   string
+	logger      *slog.Logger
+	rateLimiter *rateLimiter
 }
 
-type apiError struct {
-	Message string `json:"message,omitempty"`
-	Code    int    `json:"code,omitempty"`
+func (c *restClient) get(ctx context.Context, path string, resp any) error {
+	return c.do(ctx, http.MethodGet, path, nil, resp)
 }
 
-func (e *apiError) Error() string {
-	return fmt.Sprintf("discord api error: %d: %s", e.Code, e.Message)
+func (c *restClient) delete(ctx context.Context, path string, resp any) error {
+	return c.do(ctx, http.MethodDelete, path, nil, resp)
 }
 
-func (c *restClient) get(path string, resp any) error {
-	return c.do(http.MethodGet, path, nil, resp)
+func (c *restClient) patch(ctx context.Context, path string, req, resp any) error {
+	return c.do(ctx, http.MethodPatch, path, req, resp)
 }
 
-func (c *restClient) delete(path string, resp any) error {
-	return c.do(http.MethodDelete, path, nil, resp)
+func (c *restClient) put(ctx context.Context, path string, req, resp any) error {
+	return c.do(ctx, http.MethodPut, path, req, resp)
 }
 
-func (c *restClient) patch(path string, req, resp any) error {
-	return c.do(http.MethodPatch, path, req, resp)
-}
-
-func (c *restClient) put(path string, req, resp any) error {
-	return c.do(http.MethodPut, path, req, resp)
-}
-
-func (c *restClient) post(path string, req, resp any) error {
-	return c.do(http.MethodPost, path, req, resp)
+func (c *restClient) post(ctx context.Context, path string, req, resp any) error {
+	return c.do(ctx, http.MethodPost, path, req, resp)
 }
 
 // do is a helper function for doing a request.
-func (c *restClient) do(method string, path string, reqStruct any, respStruct any) error {
+// Requests are queued behind the rate limit bucket of their route, and retried if discord still responds with 429.
+func (c *restClient) do(ctx context.Context, method string, path string, reqStruct any, respStruct any) error {
 	u, err := url.JoinPath(c.baseURL, path)
 	if err != nil {
 		return fmt.Errorf("failed to build url: %w", err)
 	}
 
-	slog.Info("Making request.", "method", method, "path", path)
-
-	var body *bytes.Reader
+	var bs []byte
 	if reqStruct != nil {
-		bs, err := json.Marshal(reqStruct)
+		bs, err = json.Marshal(reqStruct)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request: %w", err)
 		}
-
-		body = bytes.NewReader(bs)
 	}
 
-	var req *http.Request
-	if body != nil {
-		req, err = http.NewRequest(method, u, body)
-		if req != nil {
-			req.Header.Add("Content-Type", "application/json")
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.doOnce(ctx, method, path, u, bs, respStruct, attempt < maxRateLimitRetries)
+		if err != nil {
+			return err
 		}
-	} else {
-		req, err = http.NewRequest(method, u, nil)
+
+		if retryAfter == 0 {
+			return nil
+		}
+
+		route, _ := rateLimitRoute(path)
+		c.logger.Warn("Rate limited, retrying request.", "method", method, "route", route, "retry_after", retryAfter)
+	}
+}
+
+// redactToken replaces the token in s, e.g. the path or url of a webhook or interaction request, with {token}, so
+// that it can be logged. path is the path of the request.
+func redactToken(s, path string) string {
+	segments := strings.Split(path, "/")
+
+	// The path starts with a slash, so the first segment is empty.
+	if len(segments) < 4 || (segments[1] != "webhooks" && segments[1] != "interactions") || segments[3] == "" {
+		return s
+	}
+
+	return strings.ReplaceAll(s, segments[3], "{token}")
+}
+
+// doOnce does a single attempt of a request while holding its rate limit bucket.
+// If the request was rate limited and retry is set, the time to wait is returned instead of an error.
+func (c *restClient) doOnce(ctx context.Context, method, path, u string, bs []byte, respStruct any, retry bool) (time.Duration, error) {
+	route, major := rateLimitRoute(path)
+	bucketRoute := method + " " + route
+
+	b := c.rateLimiter.bucket(bucketRoute, major)
+	if err := c.rateLimiter.acquire(ctx, b); err != nil {
+		return 0, fmt.Errorf("failed to wait for rate limit: %w", err)
 	}
+	defer c.rateLimiter.release(b)
+
+	c.logger.Info("Making request.", "method", method, "route", route)
+
+	var body io.Reader
+	if bs != nil {
+		body = bytes.NewReader(bs)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
 	req.Header.Add("User-Agent", "DiscordBot (https://github.com/hagesjo/godiscord, dev)")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to do request: %w", err)
+		// The error contains the url, and with it the token of webhook and interaction requests.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactToken(urlErr.URL, path)
+		}
+
+		return 0, fmt.Errorf("failed to do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Path:       redactToken(path, path),
+		Route:      route,
+	}
+
+	c.rateLimiter.update(b, bucketRoute, major, resp.Header)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		var rateLimitResp rateLimitResponse
+		if err := json.NewDecoder(resp.Body).Decode(&rateLimitResp); err != nil {
+			// Rate limits from the cloudflare layer in front of the API come without a JSON body.
+			apiErr.Message = http.StatusText(resp.StatusCode)
+			return 0, apiErr
+		}
+
+		retryAfter := secondsToDuration(rateLimitResp.RetryAfter)
+		global := rateLimitResp.Global || resp.Header.Get("X-RateLimit-Global") == "true"
+		c.rateLimiter.rateLimited(b, retryAfter, global)
+
+		if retry {
+			// Never report 0, since that means the request succeeded.
+			return max(retryAfter, time.Millisecond), nil
+		}
+
+		apiErr.Code = rateLimitResp.Code
+		apiErr.Message = rateLimitResp.Message
+		return 0, apiErr
 	}
 
 	if resp.StatusCode/100 != 2 {
-		var apiErr apiError
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return fmt.Errorf("failed to decode error response, status code %d: %w", resp.StatusCode, err)
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil {
+			// Not every error response has a JSON body, e.g. 502 from a proxy. The status code is still useful.
+			c.logger.Debug("Failed to decode error response.", "status", resp.StatusCode, "error", err)
 		}
 
-		return &apiErr
+		return 0, apiErr
 	}
 
 	if respStruct != nil {
-		defer resp.Body.Close()
-
 		if err := json.NewDecoder(resp.Body).Decode(respStruct); err != nil {
-			return fmt.Errorf("failed to decode json response: %w", err)
+			return 0, fmt.Errorf("failed to decode json response: %w", err)
 		}
 	}
 
-	return nil
+	return 0, nil
 }
 
 type GetGatewayURLResp struct {
@@ -151,14 +234,14 @@ type GetGatewayURLResp struct {
 	} `json:"session_start_limit"`
 }
 
-func (c *restClient) GetGatewayURL() (string, error) {
+// GetGatewayBot returns the gateway url along with the recommended number of shards and the session start limit.
+func (c *restClient) GetGatewayBot(ctx context.Context) (*GetGatewayURLResp, error) {
 	var resp GetGatewayURLResp
-	err := c.get("/gateway/bot", &resp)
-	if err != nil {
-		return "", err
+	if err := c.get(ctx, "/gateway/bot", &resp); err != nil {
+		return nil, err
 	}
 
-	return fmt.Sprintf("%s?v=%d&encoding=json", resp.URL, apiVersion), nil
+	return &resp, nil
 }
 
 // MessageCreateRequest is the request used for creating a message.
@@ -194,10 +277,10 @@ type MessageCreateResponse struct {
 	ID string `json:"id,omitempty"`
 }
 
-func (c *restClient) GetGuild(guildID string) error {
+func (c *restClient) GetGuild(ctx context.Context, guildID string) error {
 	path := fmt.Sprintf("/guilds/%s", guildID)
 	var resp Guild
-	if err := c.get(path, &resp); err != nil {
+	if err := c.get(ctx, path, &resp); err != nil {
 		return err
 	}
 
@@ -209,10 +292,10 @@ func (c *restClient) GetGuild(guildID string) error {
 // TODO: DeleteGuild
 // TODO: loooooads more.
 
-func (c *restClient) MessageSend(channelID string, req MessageCreateRequest) (*MessageCreateResponse, error) {
+func (c *restClient) MessageSend(ctx context.Context, channelID string, req MessageCreateRequest) (*MessageCreateResponse, error) {
 	path := fmt.Sprintf("/channels/%s/messages", channelID)
 	resp := &MessageCreateResponse{}
-	err := c.post(path, req, resp)
+	err := c.post(ctx, path, req, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -239,10 +322,10 @@ type CreateThreadResponse struct {
 	ID string `json:"id,omitempty"`
 }
 
-func (c *restClient) CreateThread(channelID, messageID string, req CreateThreadRequest) (*CreateThreadResponse, error) {
+func (c *restClient) CreateThread(ctx context.Context, channelID, messageID string, req CreateThreadRequest) (*CreateThreadResponse, error) {
 	path := fmt.Sprintf("/channels/%s/messages/%s/threads", channelID, messageID)
 	resp := &CreateThreadResponse{}
-	err := c.post(path, req, resp)
+	err := c.post(ctx, path, req, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -275,9 +358,9 @@ type CreateChannelRequest struct {
 	DefaultThreadRateLimitPerUser *int                `json:"default_thread_rate_limit_per_user,omitempty"`
 }
 
-func (c *restClient) CreateChannel(guildID string, req CreateChannelRequest) error {
+func (c *restClient) CreateChannel(ctx context.Context, guildID string, req CreateChannelRequest) error {
 	path := fmt.Sprintf("/guilds/%s/channels", guildID)
-	err := c.post(path, req, nil)
+	err := c.post(ctx, path, req, nil)
 	if err != nil {
 		return err
 	}
@@ -295,12 +378,129 @@ type ModifyChannelOrder struct {
 
 type ModifyChannelOrderRequest []ModifyChannelOrder
 
-func (c *restClient) ModifyChannelOrder(channelID string, req CreateChannelRequest) error {
+func (c *restClient) ModifyChannelOrder(ctx context.Context, channelID string, req CreateChannelRequest) error {
 	path := fmt.Sprintf("/guilds/%s/channels", channelID)
-	err := c.post(path, req, nil)
+	err := c.post(ctx, path, req, nil)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// BulkOverwriteGlobalApplicationCommands overwrites all global commands for the application.
+// Commands that do not already exist will count toward daily application command create limits.
+func (c *restClient) BulkOverwriteGlobalApplicationCommands(ctx context.Context, applicationID string, commands []ApplicationCommand) ([]ApplicationCommand, error) {
+	path := fmt.Sprintf("/applications/%s/commands", applicationID)
+	var resp []ApplicationCommand
+	if err := c.put(ctx, path, commands, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// BulkOverwriteGuildApplicationCommands overwrites all commands for the application in the given guild.
+func (c *restClient) BulkOverwriteGuildApplicationCommands(ctx context.Context, applicationID, guildID string, commands []ApplicationCommand) ([]ApplicationCommand, error) {
+	path := fmt.Sprintf("/applications/%s/guilds/%s/commands", applicationID, guildID)
+	var resp []ApplicationCommand
+	if err := c.put(ctx, path, commands, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// InteractionCallbackType is the type of response to an interaction.
+type InteractionCallbackType int
+
+const (
+	InteractionCallbackPong                                 InteractionCallbackType = 1 // ACK a Ping.
+	InteractionCallbackChannelMessageWithSource             InteractionCallbackType = 4 // Respond to an interaction with a message.
+	InteractionCallbackDeferredChannelMessageWithSource     InteractionCallbackType = 5 // ACK an interaction and edit a response later, the user sees a loading state.
+	InteractionCallbackDeferredUpdateMessage                InteractionCallbackType = 6 // For components, ACK an interaction and edit the original message later; the user does not see a loading state.
+	InteractionCallbackUpdateMessage                        InteractionCallbackType = 7 // For components, edit the message the component was attached to.
+	InteractionCallbackApplicationCommandAutocompleteResult InteractionCallbackType = 8 // Respond to an autocomplete interaction with suggested choices.
+	InteractionCallbackModal                                InteractionCallbackType = 9 // Respond to an interaction with a popup modal.
+)
+
+// InteractionResponse is the response sent to the interaction callback endpoint.
+type InteractionResponse struct {
+	Type InteractionCallbackType `json:"type"`           // The type of response.
+	Data any                     `json:"data,omitempty"` // An optional response message. For message responses, this is an InteractionMessage.
+}
+
+// InteractionMessage is a message used when responding to an interaction, editing the response or sending follow-ups.
+// Set Flags to MessageFlagEphemeral to only show the message to the invoking user.
+type InteractionMessage struct {
+	TTS             bool                `json:"tts,omitempty"`              // Whether the response is TTS.
+	Content         string              `json:"content,omitempty"`          // Message content.
+	Embeds          []Embed             `json:"embeds,omitempty"`           // Supports up to 10 embeds.
+	AllowedMentions *AllowedMentions    `json:"allowed_mentions,omitempty"` // Allowed mentions object.
+	Flags           int                 `json:"flags,omitempty"`            // Message flags combined as a bitfield (only SUPPRESS_EMBEDS, EPHEMERAL and SUPPRESS_NOTIFICATIONS can be set).
+	Components      []MessageActionType `json:"components,omitempty"`       // Message components.
+	Attachments     []MessageAttachment `json:"attachments,omitempty"`      // Attachment objects with filename and description.
+}
+
+// CreateInteractionResponse responds to an interaction. This must be done within 3 seconds of receiving it.
+func (c *restClient) CreateInteractionResponse(ctx context.Context, interactionID, interactionToken string, resp InteractionResponse) error {
+	path := fmt.Sprintf("/interactions/%s/%s/callback", interactionID, interactionToken)
+	return c.post(ctx, path, resp, nil)
+}
+
+// GetOriginalInteractionResponse returns the initial response to an interaction.
+func (c *restClient) GetOriginalInteractionResponse(ctx context.Context, applicationID, interactionToken string) (*Message, error) {
+	path := fmt.Sprintf("/webhooks/%s/%s/messages/@original", applicationID, interactionToken)
+	resp := &Message{}
+	if err := c.get(ctx, path, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// EditOriginalInteractionResponse edits the initial response to an interaction.
+// Interaction tokens are valid for 15 minutes.
+func (c *restClient) EditOriginalInteractionResponse(ctx context.Context, applicationID, interactionToken string, req InteractionMessage) (*Message, error) {
+	path := fmt.Sprintf("/webhooks/%s/%s/messages/@original", applicationID, interactionToken)
+	resp := &Message{}
+	if err := c.patch(ctx, path, req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// DeleteOriginalInteractionResponse deletes the initial response to an interaction.
+func (c *restClient) DeleteOriginalInteractionResponse(ctx context.Context, applicationID, interactionToken string) error {
+	path := fmt.Sprintf("/webhooks/%s/%s/messages/@original", applicationID, interactionToken)
+	return c.delete(ctx, path, nil)
+}
+
+// CreateFollowupMessage sends a follow-up message for an interaction.
+func (c *restClient) CreateFollowupMessage(ctx context.Context, applicationID, interactionToken string, req InteractionMessage) (*Message, error) {
+	path := fmt.Sprintf("/webhooks/%s/%s", applicationID, interactionToken)
+	resp := &Message{}
+	if err := c.post(ctx, path, req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// EditFollowupMessage edits a follow-up message for an interaction.
+func (c *restClient) EditFollowupMessage(ctx context.Context, applicationID, interactionToken, messageID string, req InteractionMessage) (*Message, error) {
+	path := fmt.Sprintf("/webhooks/%s/%s/messages/%s", applicationID, interactionToken, messageID)
+	resp := &Message{}
+	if err := c.patch(ctx, path, req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// DeleteFollowupMessage deletes a follow-up message for an interaction.
+func (c *restClient) DeleteFollowupMessage(ctx context.Context, applicationID, interactionToken, messageID string) error {
+	path := fmt.Sprintf("/webhooks/%s/%s/messages/%s", applicationID, interactionToken, messageID)
+	return c.delete(ctx, path, nil)
+}
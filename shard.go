@@ -0,0 +1,657 @@
+package godiscord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/hagesjo/webgockets"
+)
+
+// identifyInterval is how often a single identify bucket allows an identify.
+const identifyInterval = 5 * time.Second
+
+// Shard is a single gateway connection.
+// A shard receives the events of the guilds where (guild_id >> 22) % shard_count == shard_id, and shard 0 also receives DMs.
+type Shard struct {
+	bot     *Bot
+	manager *ShardManager
+
+	id    int
+	count int
+
+	// mu guards the current connection and its state.
+	mu        sync.Mutex
+	wsClient  *webgockets.Client
+	zlib      *zlibStream // Inflater of the connection, nil unless compression is enabled.
+	connected bool
+	state     ConnectionState
+
+	heartbeatInterval int
+	resumeGatewayURL  string
+	sessionID         string
+
+	// lastSequence and the heartbeat state are guarded by mu too, as they're shared with the heartbeater.
+	lastSequence      *uint64
+	lastHeartbeatSent time.Time
+	heartbeatAcked    bool
+	latency           time.Duration
+
+	queue  *gatewayQueue // Outbound queue of the current connection.
+	worker *eventWorker  // Runs the handlers of the received events, for as long as the shard runs.
+
+	// memberRequestsMu guards the pending guild member requests, by nonce.
+	memberRequestsMu sync.Mutex
+	memberRequests   map[string]*memberRequest
+}
+
+// ID returns the id of the shard.
+func (s *Shard) ID() int {
+	return s.id
+}
+
+// Count returns the total number of shards the bot is running with.
+func (s *Shard) Count() int {
+	return s.count
+}
+
+// ShardManager runs the gateway connections of a bot, one per shard.
+// Shards are started concurrently, but identifies are spread out as allowed by discord's max_concurrency:
+// shards share an identify bucket by shard_id % max_concurrency, and each bucket allows one identify every 5 seconds.
+type ShardManager struct {
+	bot        *Bot
+	gatewayURL string
+	count      int      // Total number of shards, including those run elsewhere.
+	shards     []*Shard // Shards run by this bot.
+
+	identifyBuckets []*identifyBucket
+
+	// sessionStartMu guards the session start limit, the number of identifies left until it resets.
+	sessionStartMu        sync.Mutex
+	sessionStartRemaining int
+	sessionStartReset     time.Time
+}
+
+type identifyBucket struct {
+	// lock is held while waiting for the bucket. It's a channel rather than a mutex so that waiting can be abandoned.
+	lock chan struct{}
+	last time.Time
+}
+
+func newShardManager(bot *Bot, gatewayURL string, gateway *GetGatewayURLResp) *ShardManager {
+	shardCount := gateway.Shards
+	if bot.shardCount > 0 {
+		shardCount = bot.shardCount
+	}
+
+	maxConcurrency := gateway.SessionStartLimit.MaxConcurrency
+	if shardCount < 1 {
+		shardCount = 1
+	}
+
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+
+	m := &ShardManager{
+		bot:        bot,
+		gatewayURL: gatewayURL,
+		count:      shardCount,
+	}
+	m.updateSessionStartLimit(gateway)
+
+	for i := 0; i < maxConcurrency; i++ {
+		m.identifyBuckets = append(m.identifyBuckets, &identifyBucket{
+			lock: make(chan struct{}, 1),
+		})
+	}
+
+	ids := bot.shardIDs
+	if len(ids) == 0 {
+		for id := 0; id < shardCount; id++ {
+			ids = append(ids, id)
+		}
+	}
+
+	for _, id := range ids {
+		m.shards = append(m.shards, &Shard{
+			bot:            bot,
+			manager:        m,
+			id:             id,
+			count:          shardCount,
+			memberRequests: make(map[string]*memberRequest),
+		})
+	}
+
+	return m
+}
+
+// Shards returns all shards.
+func (m *ShardManager) Shards() []*Shard {
+	return m.shards
+}
+
+// ShardForGuild returns the shard receiving the events of the guild, or nil if that shard isn't run by this bot.
+func (m *ShardManager) ShardForGuild(guildID string) *Shard {
+	id := shardIDForGuild(guildID, m.count)
+	for _, shard := range m.shards {
+		if shard.id == id {
+			return shard
+		}
+	}
+
+	return nil
+}
+
+// shardIDForGuild returns the id of the shard that a guild belongs to.
+func shardIDForGuild(guildID string, shardCount int) int {
+	var id uint64
+	if _, err := fmt.Sscan(guildID, &id); err != nil {
+		return 0
+	}
+
+	return int((id >> 22) % uint64(shardCount))
+}
+
+// run runs all shards until ctx is done, or until a shard fails, in which case all shards are stopped.
+func (m *ShardManager) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(m.shards))
+	for _, shard := range m.shards {
+		wg.Add(1)
+		go func(shard *Shard) {
+			defer wg.Done()
+
+			if err := shard.run(ctx); err != nil {
+				errCh <- fmt.Errorf("shard %d failed: %w", shard.id, err)
+				cancel()
+			}
+		}(shard)
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// closeConnections closes the connections of all shards.
+func (m *ShardManager) closeConnections() {
+	for _, shard := range m.shards {
+		shard.closeConnection(closeCodeNormal)
+	}
+}
+
+// waitIdentify waits until the shard is allowed to identify, as limited by both max_concurrency and the session start limit.
+func (m *ShardManager) waitIdentify(ctx context.Context, shardID int) error {
+	bucket := m.identifyBuckets[shardID%len(m.identifyBuckets)]
+
+	select {
+	case bucket.lock <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-bucket.lock }()
+
+	if wait := time.Until(bucket.last.Add(identifyInterval)); wait > 0 {
+		m.bot.logger.Info("Waiting to identify.", "shard", shardID, "wait", wait)
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+
+	if err := m.takeSessionStart(ctx); err != nil {
+		return err
+	}
+
+	bucket.last = time.Now()
+
+	return nil
+}
+
+// takeSessionStart takes one session start from the daily session start limit, waiting for it to reset if needed.
+func (m *ShardManager) takeSessionStart(ctx context.Context) error {
+	m.sessionStartMu.Lock()
+	defer m.sessionStartMu.Unlock()
+
+	for m.sessionStartRemaining <= 0 {
+		wait := time.Until(m.sessionStartReset)
+		m.bot.logger.Warn("Session start limit reached, waiting for it to reset.", "wait", wait)
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+
+		gateway, err := m.bot.restClient.GetGatewayBot(ctx)
+		if err != nil {
+			m.bot.logger.Error("Failed to refresh session start limit.", "error", err)
+			if err := sleepContext(ctx, identifyInterval); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		m.updateSessionStartLimit(gateway)
+	}
+
+	m.sessionStartRemaining--
+
+	return nil
+}
+
+func (m *ShardManager) updateSessionStartLimit(gateway *GetGatewayURLResp) {
+	m.sessionStartRemaining = gateway.SessionStartLimit.Remaining
+	m.sessionStartReset = time.Now().Add(time.Duration(gateway.SessionStartLimit.ResetAfter) * time.Millisecond)
+}
+
+// reconnect describes how a shard continues after its connection ended.
+type reconnect int
+
+const (
+	reconnectResume   reconnect = iota // Resume the session on a new connection.
+	reconnectIdentify                  // Start a new session on a new connection.
+	reconnectNone                      // Stop, either because of a shutdown or a fatal error.
+)
+
+// run connects to the gateway and reconnects when possible, until ctx is done or a fatal error occurs.
+// Failed connection attempts are retried with exponential backoff, for as long as it takes.
+func (s *Shard) run(ctx context.Context) error {
+	defer s.setState(ConnectionStateDisconnected, nil)
+
+	// The events keep their order across reconnects, as the worker outlives the connections.
+	s.worker = newEventWorker()
+	go s.worker.run()
+	defer s.worker.stop()
+
+	attempt := 0
+	var cause error
+	for {
+		if delay := reconnectDelay(attempt); delay > 0 {
+			s.setState(ConnectionStateWaitingToReconnect, cause)
+			s.bot.logger.Info("Waiting to reconnect.", "shard", s.id, "attempt", attempt, "delay", delay, "cause", cause)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil
+			}
+		}
+
+		resume := s.sessionID != ""
+		gatewayURL := s.resumeGatewayURL
+		if !resume {
+			if err := s.manager.waitIdentify(ctx, s.id); err != nil {
+				return nil
+			}
+
+			gatewayURL = s.manager.gatewayURL
+		}
+
+		s.setState(ConnectionStateConnecting, cause)
+
+		wsClient, err := webgockets.NewClient(gatewayURL)
+		if err != nil {
+			return fmt.Errorf("failed to create websocket client: %w", err)
+		}
+
+		if err := wsClient.Connect(); err != nil {
+			s.bot.logger.Warn("Failed to connect.", "shard", s.id, "attempt", attempt+1, "error", err)
+			cause = err
+			attempt++
+			continue
+		}
+
+		s.mu.Lock()
+		s.wsClient = wsClient
+		s.zlib = nil
+		if s.bot.compression {
+			// Every connection has its own zlib context.
+			s.zlib = newZlibStream()
+		}
+		s.queue = newGatewayQueue(wsClient)
+		go s.queue.run()
+		s.connected = true
+		s.mu.Unlock()
+
+		next, err := s.handler(ctx, resume)
+		wasConnected := s.State() == ConnectionStateConnected
+		if next == reconnectResume && ctx.Err() == nil {
+			s.closeConnection(closeCodeResume)
+		} else {
+			s.closeConnection(closeCodeNormal)
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		switch next {
+		case reconnectNone:
+			s.setState(ConnectionStateDisconnected, err)
+			return err
+		case reconnectIdentify:
+			s.resetSession()
+		}
+
+		cause = err
+		if wasConnected {
+			// Reconnect right away after a working connection, as most disconnects are discord asking for a reconnect.
+			attempt = 0
+		} else {
+			// The session was never established, so this counts as a failed attempt.
+			attempt++
+		}
+
+		s.bot.logger.Info("Reconnecting.", "shard", s.id, "resume", next == reconnectResume, "cause", cause)
+	}
+}
+
+// resetSession forgets the session, so that the next connection identifies instead of resuming.
+func (s *Shard) resetSession() {
+	s.sessionID = ""
+	s.resumeGatewayURL = ""
+	s.setSequence(nil)
+}
+
+// Close codes of the gateway connections. Discord ends the session right away when a connection is closed with
+// 1000 or 1001, while any other code keeps it around to be resumed.
+const (
+	closeCodeNormal = 1000
+	closeCodeResume = 4000
+)
+
+// closeWriterTimeout is how long closing a connection waits for a pending write, before closing it without a close frame.
+const closeWriterTimeout = time.Second
+
+// closeConnection closes the current gateway connection with a close frame of the given code, if connected.
+func (s *Shard) closeConnection(code uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.connected {
+		return
+	}
+
+	s.connected = false
+	s.queue.close()
+	if s.zlib != nil {
+		s.zlib.close()
+	}
+
+	// The close frame can't be written while the queue may still be writing.
+	if !s.queue.wait(closeWriterTimeout) {
+		s.bot.logger.Warn("Timed out waiting for pending writes, closing without a close frame.", "shard", s.id)
+		if err := s.wsClient.Close(); err != nil {
+			s.bot.logger.Debug("Failed to close websocket connection.", "shard", s.id, "error", err)
+		}
+
+		return
+	}
+
+	// Fails if discord already closed the connection, which is fine.
+	if err := s.wsClient.CloseWithCode(code); err != nil {
+		s.bot.logger.Debug("Failed to close websocket connection.", "shard", s.id, "error", err)
+	}
+}
+
+// reconnectAfterClose returns how to reconnect after discord closed the connection with the given close code.
+// Codes not known to be fatal, such as those of a plain websocket close, are resumed from.
+func reconnectAfterClose(code int) reconnect {
+	if sessionInvalidatingCloseEvents[code] {
+		return reconnectIdentify
+	}
+
+	if resumable, known := ResumeableCloseEvents[code]; known && !resumable {
+		return reconnectNone
+	}
+
+	return reconnectResume
+}
+
+// handler is the main listening loop for the shard, blocking until a disconnect happens or ctx is done.
+// It returns how to reconnect, along with the reason the connection ended.
+func (s *Shard) handler(ctx context.Context, resume bool) (reconnect, error) {
+	heartbeatCtx, cancelHeartbeatCtx := context.WithCancel(ctx)
+	defer cancelHeartbeatCtx()
+
+	readCtx, cancel := context.WithCancel(heartbeatCtx)
+	defer cancel()
+
+	heartbeatStarted := false
+	var heartbeatErr error
+
+	for {
+		var closeErr *webgockets.ErrClose
+		event, err := s.readEvent(readCtx)
+		if errors.As(err, &closeErr) {
+			code := int(closeErr.Code)
+			switch next := reconnectAfterClose(code); {
+			case next == reconnectIdentify:
+				return next, fmt.Errorf("session invalidated: %w", err)
+			case next == reconnectNone && (code == CloseEventShardingRequired || code == CloseEventInvalidShard):
+				return next, fmt.Errorf("discord closed the connection with code %d, the shard count must be changed: %w", code, err)
+			case next == reconnectNone:
+				return next, fmt.Errorf("discord closed the connection with code %d: %w", code, err)
+			default:
+				return next, err
+			}
+		} else if ctx.Err() != nil {
+			// Shutting down.
+			return reconnectNone, nil
+		} else if errors.Is(err, context.Canceled) {
+			return reconnectResume, fmt.Errorf("heartbeat failed: %w", heartbeatErr)
+		} else if err != nil {
+			return reconnectResume, fmt.Errorf("failed to read event: %w", err)
+		}
+
+		if event.SequenceNumber != nil {
+			s.setSequence(event.SequenceNumber)
+		}
+
+		switch event.OpCode {
+		case OpCodeDispatch:
+			s.bot.logger.Info("Got dispatch.", "shard", s.id, "type", *event.Type, "event", event)
+			if err := s.bot.dispatch(ctx, s, *event); err != nil {
+				return reconnectNone, fmt.Errorf("failed to handle dispatch event: %w", err)
+			}
+		case OpCodeResume:
+			// Do nothing for now.
+		case OpCodeReconnect:
+			return reconnectResume, fmt.Errorf("discord asked for a reconnect")
+		case OpCodeInvalidSession:
+			s.bot.logger.Info("Got invalid session.", "shard", s.id, "event", event)
+			canResume, err := UnmarshalJSON[bool](*event.Data)
+			if err != nil {
+				return reconnectNone, fmt.Errorf("discord sent invalid 'invalid session': %w", err)
+			}
+
+			if err := sleepContext(ctx, invalidSessionDelay()); err != nil {
+				return reconnectNone, nil
+			}
+
+			if !canResume {
+				return reconnectIdentify, fmt.Errorf("invalid session")
+			}
+
+			return reconnectResume, fmt.Errorf("invalid session, but resumable")
+		case OpCodeHello:
+			hello, err := UnmarshalJSON[Hello](*event.Data)
+			if err != nil {
+				return reconnectNone, fmt.Errorf("discord sent invalid hello '%s': %w", *event.Data, err)
+			}
+
+			s.bot.logger.Info("Got hello event.", "shard", s.id, "event", fmt.Sprintf("%#v", event), "hello", hello)
+
+			s.heartbeatInterval = hello.HeartbeatInterval
+			s.mu.Lock()
+			s.heartbeatAcked = true
+			s.mu.Unlock()
+
+			if !resume {
+				s.setState(ConnectionStateIdentifying, nil)
+				if err := s.identify(ctx); err != nil {
+					return reconnectIdentify, fmt.Errorf("failed to identify: %w", err)
+				}
+			} else {
+				s.setState(ConnectionStateResuming, nil)
+				if err := s.resume(ctx); err != nil {
+					return reconnectResume, fmt.Errorf("failed to resume: %w", err)
+				}
+			}
+
+			if heartbeatStarted {
+				continue
+			}
+
+			go func(ctx context.Context) {
+				heartbeatErr = s.heartbeater(ctx)
+				if heartbeatErr != nil {
+					cancelHeartbeatCtx()
+				}
+			}(heartbeatCtx)
+			heartbeatStarted = true
+
+		case OpCodeHeartbeat:
+			// Discord wants a heartbeat right away.
+			if err := s.heartbeat(ctx, false); err != nil {
+				return reconnectResume, err
+			}
+		case OpCodeHeartbeatAck:
+			s.heartbeatAck()
+		default:
+			s.bot.logger.Info("Got unknown event.", "shard", s.id, "event", fmt.Sprintf("%#v", event))
+		}
+	}
+}
+
+func (s *Shard) identify(ctx context.Context) error {
+	s.bot.mu.Lock()
+	presence := s.bot.presence
+	s.bot.mu.Unlock()
+
+	identifyPayload := Identify{
+		OpCode: OpCodeIdentity,
+		Payload: IdentifyPayload{
+			Token:   s.bot.token,
+			Intents: s.bot.intents,
+			Properties: Properties{
+				OS:      runtime.GOOS,
+				Browser: "godiscord",
+				Device:  "godiscord",
+			},
+			Presence:       presence,
+			LargeThreshold: s.bot.largeThreshold,
+			Shard:          []int{s.id, s.count},
+		},
+	}
+
+	if err := s.send(ctx, identifyPayload); err != nil {
+		return fmt.Errorf("failed to identify: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Shard) resume(ctx context.Context) error {
+	resume := Resume{
+		OpCode: OpCodeResume,
+		Payload: ResumePayload{
+			Token:     s.bot.token,
+			SessionID: s.sessionID,
+		},
+	}
+
+	if seq := s.sequence(); seq != nil {
+		resume.Payload.LastSequence = *seq
+	}
+
+	if err := s.send(ctx, resume); err != nil {
+		return fmt.Errorf("failed to resume: %w", err)
+	}
+
+	return nil
+}
+
+// readEvent reads the next gateway payload, inflating and decoding it as configured.
+func (s *Shard) readEvent(ctx context.Context) (*Event, error) {
+	for {
+		bs, err := s.wsClient.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.zlib != nil {
+			bs, err = s.zlib.inflate(bs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to inflate payload: %w", err)
+			}
+
+			if bs == nil {
+				// The rest of the payload is in the following frames.
+				continue
+			}
+		}
+
+		if s.bot.encoding == EncodingETF {
+			bs, err = etfToJSON(bs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode etf payload: %w", err)
+			}
+		}
+
+		event, err := UnmarshalJSON[Event](bs)
+		if err != nil {
+			return nil, err
+		}
+
+		return &event, nil
+	}
+}
+
+// send queues a command on the gateway connection, encoded as configured, and waits until it has been sent.
+// Commands are sent in order, as fast as the gateway rate limit allows.
+func (s *Shard) send(ctx context.Context, payload any) error {
+	return s.enqueue(ctx, payload, false)
+}
+
+// enqueue queues a payload on the outbound queue of the connection, see gatewayQueue.
+func (s *Shard) enqueue(ctx context.Context, payload any, heartbeat bool) error {
+	bs, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	binary := false
+	if s.bot.encoding == EncodingETF {
+		bs, err = jsonToETF(bs)
+		if err != nil {
+			return fmt.Errorf("failed to encode etf payload: %w", err)
+		}
+
+		binary = true
+	}
+
+	s.mu.Lock()
+	queue := s.queue
+	connected := s.connected
+	s.mu.Unlock()
+
+	if !connected {
+		return errShardNotConnected
+	}
+
+	return queue.enqueue(ctx, &gatewayWrite{
+		data:   bs,
+		binary: binary,
+		result: make(chan error, 1),
+	}, heartbeat)
+}
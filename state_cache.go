@@ -0,0 +1,448 @@
+package godiscord
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+)
+
+// StateCache stores the state received through the gateway events: guilds and their channels, threads, members,
+// roles, emojis, voice states and presences. Everything but guilds is stored per guild.
+//
+// The bot updates the cache as events are received, before passing them on to the listeners, while the getters of
+// the Bot and Fetcher read from it. Implementations must be safe for concurrent use.
+// A getter returning nothing doesn't mean that the entity doesn't exist, only that it isn't cached.
+type StateCache interface {
+	Guilds() []Guild
+	Guild(guildID string) (Guild, bool)
+	SetGuild(guild Guild)
+	DeleteGuild(guildID string) // Deletes everything cached for the guild.
+
+	Channels(guildID string) []Channel
+	Channel(guildID, channelID string) (Channel, bool)
+	SetChannel(guildID string, channel Channel)
+	DeleteChannel(guildID, channelID string)
+
+	Threads(guildID string) []Channel
+	Thread(guildID, threadID string) (Channel, bool)
+	SetThread(guildID string, thread Channel)
+	DeleteThread(guildID, threadID string)
+
+	Members(guildID string) []GuildMember
+	Member(guildID, userID string) (GuildMember, bool)
+	SetMember(guildID string, member GuildMember)
+	DeleteMember(guildID, userID string)
+
+	Roles(guildID string) []Role
+	SetRole(guildID string, role Role)
+	DeleteRole(guildID, roleID string)
+
+	Emojis(guildID string) []Emoji
+	SetEmojis(guildID string, emojis []Emoji)
+
+	VoiceStates(guildID string) []VoiceState
+	VoiceState(guildID, userID string) (VoiceState, bool)
+	SetVoiceState(guildID string, state VoiceState)
+	DeleteVoiceState(guildID, userID string)
+
+	Presences(guildID string) []PresenceUpdate
+	Presence(guildID, userID string) (PresenceUpdate, bool)
+	SetPresence(guildID string, presence PresenceUpdate)
+	DeletePresence(guildID, userID string)
+}
+
+// CacheFlags selects what a MemoryStateCache caches.
+type CacheFlags int
+
+// Cache flags.
+const (
+	CacheGuilds CacheFlags = 1 << iota
+	CacheChannels
+	CacheThreads
+	CacheMembers
+	CacheRoles  // Roles are stored with their guild, so they need CacheGuilds as well.
+	CacheEmojis // Emojis are stored with their guild, so they need CacheGuilds as well.
+	CacheVoiceStates
+	CachePresences
+
+	CacheNone CacheFlags = 0
+	CacheAll             = CacheGuilds | CacheChannels | CacheThreads | CacheMembers | CacheRoles | CacheEmojis |
+		CacheVoiceStates | CachePresences
+)
+
+// MemoryStateCache is the default StateCache, keeping the state in memory.
+//
+// What's cached is selected by its flags. The number of cached members, which is by far the largest part of the state
+// of large guilds, can be limited, in which case the least recently used members are evicted first.
+type MemoryStateCache struct {
+	flags      CacheFlags
+	maxMembers int // Max number of cached members over all guilds, 0 for no limit.
+
+	mu          sync.RWMutex
+	guilds      map[string]Guild
+	channels    map[string]map[string]Channel        // By guild id, then channel id.
+	threads     map[string]map[string]Channel        // By guild id, then thread id.
+	members     map[string]map[string]*list.Element  // By guild id, then user id. The elements are in memberLRU.
+	memberLRU   *list.List                           // Cached members, most recently used first.
+	voiceStates map[string]map[string]VoiceState     // By guild id, then user id.
+	presences   map[string]map[string]PresenceUpdate // By guild id, then user id.
+}
+
+// memberEntry is a member in the LRU list of a MemoryStateCache.
+type memberEntry struct {
+	guildID string
+	member  GuildMember
+}
+
+// NewMemoryStateCache creates an in-memory state cache, caching what's selected by flags.
+// maxMembers limits the number of cached members over all guilds, evicting the least recently used ones, 0 for no limit.
+func NewMemoryStateCache(flags CacheFlags, maxMembers int) *MemoryStateCache {
+	return &MemoryStateCache{
+		flags:       flags,
+		maxMembers:  maxMembers,
+		guilds:      make(map[string]Guild),
+		channels:    make(map[string]map[string]Channel),
+		threads:     make(map[string]map[string]Channel),
+		members:     make(map[string]map[string]*list.Element),
+		memberLRU:   list.New(),
+		voiceStates: make(map[string]map[string]VoiceState),
+		presences:   make(map[string]map[string]PresenceUpdate),
+	}
+}
+
+func (c *MemoryStateCache) Guilds() []Guild {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Values(c.guilds)
+}
+
+func (c *MemoryStateCache) Guild(guildID string) (Guild, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	guild, ok := c.guilds[guildID]
+	return guild, ok
+}
+
+func (c *MemoryStateCache) SetGuild(guild Guild) {
+	if c.flags&CacheGuilds == 0 {
+		return
+	}
+
+	if c.flags&CacheRoles == 0 {
+		guild.Roles = nil
+	}
+
+	if c.flags&CacheEmojis == 0 {
+		guild.Emojis = nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.guilds[guild.ID] = guild
+}
+
+func (c *MemoryStateCache) DeleteGuild(guildID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.guilds, guildID)
+	delete(c.channels, guildID)
+	delete(c.threads, guildID)
+	delete(c.voiceStates, guildID)
+	delete(c.presences, guildID)
+
+	for _, element := range c.members[guildID] {
+		c.memberLRU.Remove(element)
+	}
+	delete(c.members, guildID)
+}
+
+func (c *MemoryStateCache) Channels(guildID string) []Channel {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Values(c.channels[guildID])
+}
+
+func (c *MemoryStateCache) Channel(guildID, channelID string) (Channel, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	channel, ok := c.channels[guildID][channelID]
+	return channel, ok
+}
+
+func (c *MemoryStateCache) SetChannel(guildID string, channel Channel) {
+	if c.flags&CacheChannels == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	setByGuild(c.channels, guildID, channel.ID, channel)
+}
+
+func (c *MemoryStateCache) DeleteChannel(guildID, channelID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.channels[guildID], channelID)
+}
+
+func (c *MemoryStateCache) Threads(guildID string) []Channel {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Values(c.threads[guildID])
+}
+
+func (c *MemoryStateCache) Thread(guildID, threadID string) (Channel, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	thread, ok := c.threads[guildID][threadID]
+	return thread, ok
+}
+
+func (c *MemoryStateCache) SetThread(guildID string, thread Channel) {
+	if c.flags&CacheThreads == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	setByGuild(c.threads, guildID, thread.ID, thread)
+}
+
+func (c *MemoryStateCache) DeleteThread(guildID, threadID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.threads[guildID], threadID)
+}
+
+func (c *MemoryStateCache) Members(guildID string) (members []GuildMember) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, element := range c.members[guildID] {
+		members = append(members, element.Value.(*memberEntry).member)
+	}
+
+	return members
+}
+
+func (c *MemoryStateCache) Member(guildID, userID string) (GuildMember, bool) {
+	if c.maxMembers == 0 {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+	} else {
+		// Looking a member up marks it as recently used, which modifies the LRU list.
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+
+	element, ok := c.members[guildID][userID]
+	if !ok {
+		return GuildMember{}, false
+	}
+
+	if c.maxMembers > 0 {
+		c.memberLRU.MoveToFront(element)
+	}
+
+	return element.Value.(*memberEntry).member, true
+}
+
+func (c *MemoryStateCache) SetMember(guildID string, member GuildMember) {
+	if c.flags&CacheMembers == 0 || member.User == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.members[guildID][member.User.ID]; ok {
+		element.Value.(*memberEntry).member = member
+		c.memberLRU.MoveToFront(element)
+		return
+	}
+
+	element := c.memberLRU.PushFront(&memberEntry{
+		guildID: guildID,
+		member:  member,
+	})
+	setByGuild(c.members, guildID, member.User.ID, element)
+
+	if c.maxMembers > 0 && c.memberLRU.Len() > c.maxMembers {
+		c.removeMember(c.memberLRU.Back())
+	}
+}
+
+func (c *MemoryStateCache) DeleteMember(guildID, userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.members[guildID][userID]; ok {
+		c.removeMember(element)
+	}
+}
+
+// removeMember removes a member from the cache. Must be called with mu held.
+func (c *MemoryStateCache) removeMember(element *list.Element) {
+	entry := c.memberLRU.Remove(element).(*memberEntry)
+	delete(c.members[entry.guildID], entry.member.User.ID)
+}
+
+func (c *MemoryStateCache) Roles(guildID string) []Role {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.guilds[guildID].Roles
+}
+
+func (c *MemoryStateCache) SetRole(guildID string, role Role) {
+	if c.flags&CacheRoles == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	guild, ok := c.guilds[guildID]
+	if !ok {
+		return
+	}
+
+	// The roles are copied rather than updated in place, as guilds handed out by the getters share them.
+	roles := slices.Clone(guild.Roles)
+	if i := slices.IndexFunc(roles, func(r Role) bool { return r.ID == role.ID }); i >= 0 {
+		roles[i] = role
+	} else {
+		roles = append(roles, role)
+	}
+
+	guild.Roles = roles
+	c.guilds[guildID] = guild
+}
+
+func (c *MemoryStateCache) DeleteRole(guildID, roleID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	guild, ok := c.guilds[guildID]
+	if !ok {
+		return
+	}
+
+	guild.Roles = slices.DeleteFunc(slices.Clone(guild.Roles), func(role Role) bool {
+		return role.ID == roleID
+	})
+	c.guilds[guildID] = guild
+}
+
+func (c *MemoryStateCache) Emojis(guildID string) []Emoji {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.guilds[guildID].Emojis
+}
+
+func (c *MemoryStateCache) SetEmojis(guildID string, emojis []Emoji) {
+	if c.flags&CacheEmojis == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	guild, ok := c.guilds[guildID]
+	if !ok {
+		return
+	}
+
+	guild.Emojis = emojis
+	c.guilds[guildID] = guild
+}
+
+func (c *MemoryStateCache) VoiceStates(guildID string) []VoiceState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Values(c.voiceStates[guildID])
+}
+
+func (c *MemoryStateCache) VoiceState(guildID, userID string) (VoiceState, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	state, ok := c.voiceStates[guildID][userID]
+	return state, ok
+}
+
+func (c *MemoryStateCache) SetVoiceState(guildID string, state VoiceState) {
+	if c.flags&CacheVoiceStates == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	setByGuild(c.voiceStates, guildID, state.UserID, state)
+}
+
+func (c *MemoryStateCache) DeleteVoiceState(guildID, userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.voiceStates[guildID], userID)
+}
+
+func (c *MemoryStateCache) Presences(guildID string) []PresenceUpdate {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Values(c.presences[guildID])
+}
+
+func (c *MemoryStateCache) Presence(guildID, userID string) (PresenceUpdate, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	presence, ok := c.presences[guildID][userID]
+	return presence, ok
+}
+
+func (c *MemoryStateCache) SetPresence(guildID string, presence PresenceUpdate) {
+	if c.flags&CachePresences == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	setByGuild(c.presences, guildID, presence.User.ID, presence)
+}
+
+func (c *MemoryStateCache) DeletePresence(guildID, userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.presences[guildID], userID)
+}
+
+// setByGuild sets a value in a map of maps by guild id, creating the guild's map if needed.
+func setByGuild[V any](m map[string]map[string]V, guildID, id string, v V) {
+	byID, ok := m[guildID]
+	if !ok {
+		byID = make(map[string]V)
+		m[guildID] = byID
+	}
+
+	byID[id] = v
+}
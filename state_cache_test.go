@@ -0,0 +1,138 @@
+package godiscord
+
+import (
+	"testing"
+)
+
+func testMember(userID string) GuildMember {
+	return GuildMember{User: &User{ID: userID}}
+}
+
+func TestMemoryStateCacheEvictsLeastRecentlyUsedMembers(t *testing.T) {
+	c := NewMemoryStateCache(CacheAll, 3)
+	c.SetMember("1", testMember("a"))
+	c.SetMember("1", testMember("b"))
+	c.SetMember("2", testMember("c"))
+
+	// Looking a member up or updating it makes it the most recently used one, leaving b the least recently used.
+	c.Member("1", "a")
+	c.SetMember("2", testMember("c"))
+
+	c.SetMember("2", testMember("d"))
+	if _, ok := c.Member("1", "b"); ok {
+		t.Errorf("b wasn't evicted")
+	}
+
+	for _, m := range []struct{ guildID, userID string }{{"1", "a"}, {"2", "c"}, {"2", "d"}} {
+		if _, ok := c.Member(m.guildID, m.userID); !ok {
+			t.Errorf("%s was evicted", m.userID)
+		}
+	}
+
+	// The limit is over all guilds: a is now the least recently used.
+	c.SetMember("3", testMember("e"))
+	if _, ok := c.Member("1", "a"); ok {
+		t.Errorf("a wasn't evicted")
+	}
+
+	if members := c.Members("1"); len(members) != 0 {
+		t.Errorf("guild 1 has %d members, want 0", len(members))
+	}
+}
+
+func TestMemoryStateCacheDisabledFlagsStoreNothing(t *testing.T) {
+	c := NewMemoryStateCache(CacheNone, 0)
+	c.SetGuild(Guild{ID: "1"})
+	c.SetChannel("1", Channel{ID: "2"})
+	c.SetThread("1", Channel{ID: "3"})
+	c.SetMember("1", testMember("4"))
+	c.SetVoiceState("1", VoiceState{UserID: "4"})
+	c.SetPresence("1", PresenceUpdate{User: User{ID: "4"}})
+
+	if guilds := c.Guilds(); len(guilds) != 0 {
+		t.Errorf("got %d guilds, want 0", len(guilds))
+	}
+
+	if channels := c.Channels("1"); len(channels) != 0 {
+		t.Errorf("got %d channels, want 0", len(channels))
+	}
+
+	if threads := c.Threads("1"); len(threads) != 0 {
+		t.Errorf("got %d threads, want 0", len(threads))
+	}
+
+	if members := c.Members("1"); len(members) != 0 {
+		t.Errorf("got %d members, want 0", len(members))
+	}
+
+	if states := c.VoiceStates("1"); len(states) != 0 {
+		t.Errorf("got %d voice states, want 0", len(states))
+	}
+
+	if presences := c.Presences("1"); len(presences) != 0 {
+		t.Errorf("got %d presences, want 0", len(presences))
+	}
+
+	// Roles and emojis are stripped from guilds unless they're cached too.
+	c = NewMemoryStateCache(CacheGuilds, 0)
+	c.SetGuild(Guild{ID: "1", Roles: []Role{{ID: "2"}}, Emojis: []Emoji{{Roles: []string{"2"}}}})
+	c.SetRole("1", Role{ID: "4"})
+	if roles := c.Roles("1"); len(roles) != 0 {
+		t.Errorf("got %d roles, want 0", len(roles))
+	}
+
+	if emojis := c.Emojis("1"); len(emojis) != 0 {
+		t.Errorf("got %d emojis, want 0", len(emojis))
+	}
+}
+
+func TestMemoryStateCacheDeleteGuildDropsItsMembers(t *testing.T) {
+	c := NewMemoryStateCache(CacheAll, 2)
+	c.SetGuild(Guild{ID: "1"})
+	c.SetMember("1", testMember("a"))
+	c.SetMember("2", testMember("b"))
+	c.SetChannel("1", Channel{ID: "3"})
+
+	c.DeleteGuild("1")
+	if _, ok := c.Guild("1"); ok {
+		t.Errorf("guild wasn't deleted")
+	}
+
+	if members := c.Members("1"); len(members) != 0 {
+		t.Errorf("got %d members, want 0", len(members))
+	}
+
+	if channels := c.Channels("1"); len(channels) != 0 {
+		t.Errorf("got %d channels, want 0", len(channels))
+	}
+
+	// The deleted members no longer count towards the limit, so b is kept.
+	c.SetMember("2", testMember("c"))
+	if _, ok := c.Member("2", "b"); !ok {
+		t.Errorf("b was evicted")
+	}
+}
+
+func TestWithStateCache(t *testing.T) {
+	b := newTestBot()
+	cache := NewMemoryStateCache(CacheAll&^CacheChannels, 0)
+	WithStateCache(cache)(b)
+	b.fetchersByGuild["1"] = &Fetcher{}
+
+	shard := &Shard{memberRequests: make(map[string]*memberRequest)}
+	if _, err := b.updateCache(shard, "CHANNEL_CREATE", []byte(`{"id": "2", "type": 0, "guild_id": "1", "name": "channel"}`)); err != nil {
+		t.Fatalf("failed to update cache: %v", err)
+	}
+
+	if _, ok := cache.Channel("1", "2"); ok {
+		t.Errorf("channel was cached")
+	}
+
+	if _, err := b.updateCache(shard, "THREAD_CREATE", []byte(`{"id": "3", "type": 11, "guild_id": "1", "parent_id": "2", "name": "thread"}`)); err != nil {
+		t.Fatalf("failed to update cache: %v", err)
+	}
+
+	if _, ok := cache.Thread("1", "3"); !ok {
+		t.Errorf("thread wasn't cached")
+	}
+}
@@ -690,6 +690,18 @@ type Sticker struct {
 	SortValue   *int              `json:"sort_value,omitempty"`
 }
 
+// SoundboardSound is a sound that can be played in voice channels.
+type SoundboardSound struct {
+	Name      string  `json:"name"`               // Name of the sound.
+	SoundID   string  `json:"sound_id"`           // ID of the sound.
+	Volume    float64 `json:"volume"`             // Volume of the sound, from 0 to 1.
+	EmojiID   *string `json:"emoji_id"`           // ID of the sound's custom emoji.
+	EmojiName *string `json:"emoji_name"`         // Unicode character of the sound's standard emoji.
+	GuildID   string  `json:"guild_id,omitempty"` // ID of the guild the sound is in. Empty for the default sounds.
+	Available bool    `json:"available"`          // Whether the sound can be used, may be false due to loss of server boosts.
+	User      *User   `json:"user,omitempty"`     // User who created the sound.
+}
+
 type StickerType int
 
 const (
@@ -837,21 +849,6 @@ type AutoModerationActionMetadata struct {
 	CustomMessage   *string `json:"custom_message,omitempty"` // Additional explanation that will be shown to members whenever their message is blocked for BLOCK_MESSAGE action type.
 }
 
-// AutoModerationActionExecutionEvent is that occurs when an action is executed in auto moderation.
-type AutoModerationActionExecutionEvent struct {
-	GuildID              string                        `json:"guild_id"`                          // ID of the guild in which action was executed.
-	Action               AutoModerationAction          `json:"action"`                            // Action which was executed.
-	RuleID               string                        `json:"rule_id"`                           // ID of the rule which action belongs to.
-	RuleTriggerType      AutoModerationRuleTriggerType `json:"rule_trigger_type"`                 // Trigger type of rule which was triggered.
-	UserID               string                        `json:"user_id"`                           // ID of the user which generated the content which triggered the rule.
-	ChannelID            *string                       `json:"channel_id,omitempty"`              // ID of the channel in which user content was posted.
-	MessageID            *string                       `json:"message_id,omitempty"`              // ID of any user message which content belongs to.
-	AlertSystemMessageID *string                       `json:"alert_system_message_id,omitempty"` // ID of any system auto moderation messages posted as a result of this action.
-	Content              string                        `json:"content"`                           // User-generated text content.
-	MatchedKeyword       *string                       `json:"matched_keyword,omitempty"`         // Word or phrase configured in the rule that triggered the rule.
-	MatchedContent       *string                       `json:"matched_content,omitempty"`         // Substring in content that triggered the rule.
-}
-
 // Entitlement represents an entitlement object.
 type Entitlement struct {
 	ID            string          `json:"id"`                  // ID of the entitlement.
@@ -1261,6 +1258,18 @@ const (
 	GuildApplicationPremiumSubscriptionMessageType                        // 32 (Deletable: false)
 )
 
+// MessageFlag represents the flags of a message, combined as a bitfield.
+const (
+	MessageFlagCrossposted           = 1 << 0  // This message has been published to subscribed channels (via Channel Following).
+	MessageFlagIsCrosspost           = 1 << 1  // This message originated from a message in another channel (via Channel Following).
+	MessageFlagSuppressEmbeds        = 1 << 2  // Do not include any embeds when serializing this message.
+	MessageFlagUrgent                = 1 << 4  // This message came from the urgent message system.
+	MessageFlagHasThread             = 1 << 5  // This message has an associated thread, with the same id as the message.
+	MessageFlagEphemeral             = 1 << 6  // This message is only visible to the user who invoked the Interaction.
+	MessageFlagLoading               = 1 << 7  // This message is an Interaction Response and the bot is "thinking".
+	MessageFlagSuppressNotifications = 1 << 12 // This message will not trigger push and desktop notifications.
+)
+
 type MessageActivity struct {
 	Type    MessageActivityType `json:"type"`     // type of message activity
 	PartyID *string             `json:"party_id"` // party_id from a Rich Presence event.
@@ -1311,13 +1320,14 @@ type baseComponent struct {
 // MessageActionType is what discord calls components.
 // The reason why it's called action type here is because the object is nestled in itself, calling itself an action type in that context.
 type MessageActionType struct {
-	Type ComponentType
+	Type ComponentType `json:"type"`
 	// Components is a list of Button/SelectMenu/TextInput objects.
 	Components []json.RawMessage `json:"components"`
 }
 
 // GetComponents unmarshals the components based on the type into a array.
-// To access these, use a type switch.
+// The elements are Button, SelectMenu (for all select types) or TextInput. To access these, use a type switch.
+// Components of an unknown type are returned as their json.RawMessage.
 func (mat *MessageActionType) GetComponents() ([]any, error) {
 	var ret []any
 	for _, component := range mat.Components {
@@ -1330,10 +1340,28 @@ func (mat *MessageActionType) GetComponents() ([]any, error) {
 		case ComponentTypeButton:
 			button, err := UnmarshalJSON[Button](component)
 			if err != nil {
-				return nil, fmt.Errorf("failed to unmarshal component type: %w", err)
+				return nil, fmt.Errorf("failed to unmarshal button: %w", err)
 			}
 
 			ret = append(ret, button)
+		case ComponentTypeStringSelect, ComponentTypeUserSelect, ComponentTypeRoleSelect,
+			ComponentTypeMentionableSelect, ComponentTypeChannelSelect:
+			selectMenu, err := UnmarshalJSON[SelectMenu](component)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unmarshal select menu: %w", err)
+			}
+
+			ret = append(ret, selectMenu)
+		case ComponentTypeTextInput:
+			textInput, err := UnmarshalJSON[TextInput](component)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unmarshal text input: %w", err)
+			}
+
+			ret = append(ret, textInput)
+		default:
+			// Component types added by discord later on are kept as is, rather than failing the whole message.
+			ret = append(ret, component)
 		}
 	}
 
@@ -0,0 +1,174 @@
+package godiscord
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultVoiceJoinTimeout is how long JoinVoiceChannel waits for discord to send the voice connection details.
+const defaultVoiceJoinTimeout = 10 * time.Second
+
+// VoiceConnectionInfo is what's needed to connect to the voice server of a guild, after joining one of its voice channels.
+type VoiceConnectionInfo struct {
+	GuildID   string // Guild of the voice channel.
+	ChannelID string // Voice channel that was joined.
+	UserID    string // User id of the bot.
+	SessionID string // Voice session id, from VOICE_STATE_UPDATE.
+	Token     string // Voice connection token, from VOICE_SERVER_UPDATE.
+	Endpoint  string // Voice server host, from VOICE_SERVER_UPDATE.
+}
+
+// voiceJoin is a pending join of a voice channel, waiting for both the VOICE_STATE_UPDATE and VOICE_SERVER_UPDATE.
+type voiceJoin struct {
+	info      VoiceConnectionInfo
+	gotState  bool
+	gotServer bool
+	done      chan struct{} // Closed once both events have been received.
+}
+
+// JoinVoiceChannel joins a voice channel, and returns the details needed to connect to its voice server.
+// mute and deaf set whether the bot is self muted and deafened.
+//
+// The details arrive as events, which the shard matches before passing them on to the handlers, so it can be called
+// from an event listener or command handler.
+func (b *Bot) JoinVoiceChannel(guildID, channelID string, mute, deaf bool) (*VoiceConnectionInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultVoiceJoinTimeout)
+	defer cancel()
+
+	return b.JoinVoiceChannelContext(ctx, guildID, channelID, mute, deaf)
+}
+
+// JoinVoiceChannelContext is like JoinVoiceChannel, but waits until ctx is done rather than for a default timeout.
+func (b *Bot) JoinVoiceChannelContext(ctx context.Context, guildID, channelID string, mute, deaf bool) (*VoiceConnectionInfo, error) {
+	shard := b.shardManager.ShardForGuild(guildID)
+	if shard == nil {
+		return nil, fmt.Errorf("the shard of guild %s isn't run by this bot", guildID)
+	}
+
+	join := &voiceJoin{
+		info: VoiceConnectionInfo{
+			GuildID:   guildID,
+			ChannelID: channelID,
+		},
+		done: make(chan struct{}),
+	}
+
+	b.voiceMu.Lock()
+	if _, ok := b.voiceJoins[guildID]; ok {
+		b.voiceMu.Unlock()
+		return nil, fmt.Errorf("already joining a voice channel in guild %s", guildID)
+	}
+	b.voiceJoins[guildID] = join
+	b.voiceMu.Unlock()
+
+	defer func() {
+		b.voiceMu.Lock()
+		delete(b.voiceJoins, guildID)
+		b.voiceMu.Unlock()
+	}()
+
+	err := shard.send(ctx, UpdateVoiceState{
+		OpCode: OpCodeVoiceStateUpdate,
+		Payload: UpdateVoiceStatePayload{
+			GuildID:   guildID,
+			ChannelID: &channelID,
+			SelfMute:  mute,
+			SelfDeaf:  deaf,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update voice state: %w", err)
+	}
+
+	select {
+	case <-join.done:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to get voice connection details: %w", ctx.Err())
+	}
+
+	b.voiceMu.Lock()
+	defer b.voiceMu.Unlock()
+
+	info := join.info
+	return &info, nil
+}
+
+// LeaveVoiceChannel leaves the voice channel the bot is in, in the given guild.
+func (b *Bot) LeaveVoiceChannel(guildID string) error {
+	return b.LeaveVoiceChannelContext(context.Background(), guildID)
+}
+
+// LeaveVoiceChannelContext is like LeaveVoiceChannel, but gives up waiting for the gateway rate limit when ctx is done.
+func (b *Bot) LeaveVoiceChannelContext(ctx context.Context, guildID string) error {
+	shard := b.shardManager.ShardForGuild(guildID)
+	if shard == nil {
+		return fmt.Errorf("the shard of guild %s isn't run by this bot", guildID)
+	}
+
+	err := shard.send(ctx, UpdateVoiceState{
+		OpCode: OpCodeVoiceStateUpdate,
+		Payload: UpdateVoiceStatePayload{
+			GuildID: guildID,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update voice state: %w", err)
+	}
+
+	return nil
+}
+
+// voiceStateUpdated completes the voice state part of a pending join, if the update is the bot's own.
+func (b *Bot) voiceStateUpdated(state VoiceState) {
+	if state.GuildID == nil || state.ChannelID == nil || state.UserID != b.userID {
+		return
+	}
+
+	b.voiceMu.Lock()
+	defer b.voiceMu.Unlock()
+
+	join, ok := b.voiceJoins[*state.GuildID]
+	if !ok || join.info.ChannelID != *state.ChannelID {
+		return
+	}
+
+	join.info.UserID = state.UserID
+	join.info.SessionID = state.SessionID
+	join.gotState = true
+	join.complete()
+}
+
+// voiceServerUpdated completes the voice server part of a pending join.
+func (b *Bot) voiceServerUpdated(update VoiceServerUpdate) {
+	// A missing endpoint means that the voice server went away, and another one will be sent once allocated.
+	if update.Endpoint == nil {
+		return
+	}
+
+	b.voiceMu.Lock()
+	defer b.voiceMu.Unlock()
+
+	join, ok := b.voiceJoins[update.GuildID]
+	if !ok {
+		return
+	}
+
+	join.info.Token = update.Token
+	join.info.Endpoint = *update.Endpoint
+	join.gotServer = true
+	join.complete()
+}
+
+// complete marks the join as done once both events have been received. Must be called with voiceMu held.
+func (j *voiceJoin) complete() {
+	if !j.gotState || !j.gotServer {
+		return
+	}
+
+	select {
+	case <-j.done:
+	default:
+		close(j.done)
+	}
+}